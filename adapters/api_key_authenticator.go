@@ -0,0 +1,72 @@
+package adapters
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// APIKeyHeader carries a static API key.
+const APIKeyHeader = "X-API-Key"
+
+type apiKey struct {
+	name string
+	role domain.Role
+	hash [sha256.Size]byte
+}
+
+// APIKeyAuthenticator accepts the static keys configured in AUTH_API_KEYS.
+type APIKeyAuthenticator struct {
+	keys []apiKey
+}
+
+// NewAPIKeyAuthenticator parses a comma-separated list of name:role:key
+// entries. The name becomes the caller's subject in the audit log.
+func NewAPIKeyAuthenticator(spec string) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, rest, _ := strings.Cut(entry, ":")
+		roleName, key, _ := strings.Cut(rest, ":")
+		if name == "" || key == "" {
+			return nil, fmt.Errorf("api key %q: want name:role:key", name)
+		}
+		role, ok := domain.ParseRole(roleName)
+		if !ok {
+			return nil, fmt.Errorf("api key %q: unknown role %q", name, roleName)
+		}
+		a.keys = append(a.keys, apiKey{name: name, role: role, hash: sha256.Sum256([]byte(key))})
+	}
+	if len(a.keys) == 0 {
+		return nil, fmt.Errorf("no api keys configured")
+	}
+	return a, nil
+}
+
+// Authenticate returns nil when r has no API key. Every configured key is
+// compared, in constant time, so the response time does not reveal a match.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*domain.Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+
+	hash := sha256.Sum256([]byte(key))
+	var match *apiKey
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], a.keys[i].hash[:]) == 1 {
+			match = &a.keys[i]
+		}
+	}
+	if match == nil {
+		return nil, domain.ErrUnauthenticated
+	}
+	return &domain.Principal{Subject: match.name, Roles: []domain.Role{match.role}}, nil
+}
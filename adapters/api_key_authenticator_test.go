@@ -0,0 +1,56 @@
+package adapters_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	a, err := adapters.NewAPIKeyAuthenticator("ops:admin:s3cret, storefront:calculator:k:with:colons")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		Name      string
+		Key       string
+		Principal *domain.Principal
+		Err       error
+	}{
+		{Name: "no key"},
+		{
+			Name:      "admin key",
+			Key:       "s3cret",
+			Principal: &domain.Principal{Subject: "ops", Roles: []domain.Role{domain.RoleAdmin}},
+		},
+		{
+			Name:      "key containing colons",
+			Key:       "k:with:colons",
+			Principal: &domain.Principal{Subject: "storefront", Roles: []domain.Role{domain.RoleCalculator}},
+		},
+		{Name: "unknown key", Key: "guess", Err: domain.ErrUnauthenticated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/pack-sizes", nil)
+			if tc.Key != "" {
+				r.Header.Set(adapters.APIKeyHeader, tc.Key)
+			}
+
+			p, err := a.Authenticate(r)
+			require.ErrorIs(t, err, tc.Err)
+			require.Equal(t, tc.Principal, p)
+		})
+	}
+}
+
+func TestNewAPIKeyAuthenticatorRejectsInvalidConfig(t *testing.T) {
+	for _, spec := range []string{"", "ops:admin", "ops:owner:s3cret", ":admin:s3cret"} {
+		_, err := adapters.NewAPIKeyAuthenticator(spec)
+		require.Error(t, err, spec)
+	}
+}
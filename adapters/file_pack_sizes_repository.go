@@ -0,0 +1,190 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/packio"
+	"github.com/sirupsen/logrus"
+)
+
+// fileReloadDelay coalesces the bursts of events editors and ConfigMap
+// updates produce for a single change.
+const fileReloadDelay = 100 * time.Millisecond
+
+// FilePackSizesRepository serves pack sizes declared in a CSV, JSON or YAML
+// file, validated like a set written through the API. Watch reloads the file
+// on change; an invalid revision is logged and the previous set stays
+// active. Writes are rejected with domain.ErrPackSizesReadOnly.
+type FilePackSizesRepository struct {
+	path   string
+	format packio.Format
+	limits domain.InputLimits
+	// state is versioned by the number of distinct sets loaded so far.
+	state atomic.Pointer[domain.VersionedPackSizes]
+
+	mu          sync.Mutex
+	subscribers []func()
+
+	watcher   *fsnotify.Watcher
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewFilePackSizesRepository loads path once and fails if it is not a valid
+// pack-size file. The format is taken from the file extension.
+func NewFilePackSizesRepository(path string, limits domain.InputLimits) (*FilePackSizesRepository, error) {
+	format, err := packio.FormatFromFilename(path)
+	if err != nil {
+		return nil, fmt.Errorf("pack sizes file %s: %w", path, err)
+	}
+
+	r := &FilePackSizesRepository{
+		path:   filepath.Clean(path),
+		format: format,
+		limits: limits,
+		done:   make(chan struct{}),
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *FilePackSizesRepository) GetPackSizes(ctx context.Context) ([]domain.SmartPack, error) {
+	return slices.Clone(r.state.Load().Packs), nil
+}
+
+func (r *FilePackSizesRepository) GetVersionedPackSizes(ctx context.Context) (domain.VersionedPackSizes, error) {
+	state := r.state.Load()
+	return domain.VersionedPackSizes{Version: state.Version, Packs: slices.Clone(state.Packs)}, nil
+}
+
+// Subscribe registers fn to be called whenever a reload changes the set.
+func (r *FilePackSizesRepository) Subscribe(fn func()) {
+	r.mu.Lock()
+	r.subscribers = append(r.subscribers, fn)
+	r.mu.Unlock()
+}
+
+func (r *FilePackSizesRepository) SetPackSizes(ctx context.Context, sizes []domain.SmartPack) ([]domain.SmartPack, error) {
+	return nil, domain.ErrPackSizesReadOnly
+}
+
+// Watch starts reloading the file whenever it changes until Close is called.
+// The parent directory is watched so that atomic renames and symlink swaps
+// are picked up as well as in-place writes.
+func (r *FilePackSizesRepository) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(r.path)); err != nil {
+		watcher.Close()
+		return err
+	}
+	r.watcher = watcher
+
+	r.wg.Add(1)
+	go r.watch()
+	return nil
+}
+
+func (r *FilePackSizesRepository) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.done)
+		if r.watcher != nil {
+			err = r.watcher.Close()
+		}
+		r.wg.Wait()
+	})
+	return err
+}
+
+func (r *FilePackSizesRepository) watch() {
+	defer r.wg.Done()
+
+	timer := time.NewTimer(fileReloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case _, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			timer.Reset(fileReloadDelay)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			logrus.WithError(err).WithField("path", r.path).Error("Pack sizes file watcher failed")
+		case <-timer.C:
+			if err := r.reload(); err != nil {
+				logrus.WithError(err).WithField("path", r.path).
+					Error("Ignoring invalid pack sizes file, keeping the previous pack sizes")
+			}
+		}
+	}
+}
+
+// reload reads and validates the file, then swaps it in as the active set.
+func (r *FilePackSizesRepository) reload() error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoded, err := packio.Decode(f, r.format)
+	if err != nil {
+		return fmt.Errorf("pack sizes file %s: %w", r.path, err)
+	}
+	if len(decoded.Errors) > 0 {
+		errs := make([]error, 0, len(decoded.Errors))
+		for _, rowErr := range decoded.Errors {
+			errs = append(errs, rowErr)
+		}
+		return fmt.Errorf("pack sizes file %s: %w", r.path, errors.Join(errs...))
+	}
+	packs := decoded.Packs
+	if err := domain.ValidatePackSizes(packs, r.limits); err != nil {
+		return fmt.Errorf("pack sizes file %s: %w", r.path, err)
+	}
+
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Size > packs[j].Size })
+	previous := r.state.Load()
+	if previous == nil {
+		r.state.Store(&domain.VersionedPackSizes{Version: 1, Packs: packs})
+		return nil
+	}
+	if slices.Equal(previous.Packs, packs) {
+		return nil
+	}
+
+	r.state.Store(&domain.VersionedPackSizes{Version: previous.Version + 1, Packs: packs})
+	logrus.WithField("path", r.path).WithField("pack_sizes", len(packs)).Info("Reloaded pack sizes from file")
+
+	r.mu.Lock()
+	subscribers := slices.Clone(r.subscribers)
+	r.mu.Unlock()
+	for _, fn := range subscribers {
+		fn()
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package adapters_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func writePackSizesFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	// Write and rename like editors and ConfigMap updates do.
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func TestFilePackSizesRepository(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "pack-sizes.yaml")
+	writePackSizesFile(t, path, "packs:\n  - size: 250\n  - size: 500\n    label: Box\n")
+
+	repo, err := adapters.NewFilePackSizesRepository(path, domain.DefaultInputLimits)
+	require.NoError(t, err)
+	require.NoError(t, repo.Watch())
+	defer repo.Close()
+
+	sizes, err := repo.GetPackSizes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []domain.SmartPack{
+		{Size: 500, Label: "Box", Enabled: true},
+		{Size: 250, Enabled: true},
+	}, sizes)
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		_, err := repo.SetPackSizes(ctx, []domain.SmartPack{{Size: 1, Enabled: true}})
+		v, ok := domain.IsHTTPCustomError(err)
+		require.True(t, ok)
+		require.Equal(t, domain.ErrorPackSizesReadOnlyLabel, v.Label())
+		require.Equal(t, 409, v.Status())
+	})
+
+	t.Run("changes are reloaded", func(t *testing.T) {
+		changed := make(chan struct{}, 1)
+		repo.Subscribe(func() { changed <- struct{}{} })
+
+		writePackSizesFile(t, path, "packs:\n  - size: 23\n  - size: 31\n  - size: 53\n")
+
+		require.Eventually(t, func() bool {
+			sizes, _ := repo.GetPackSizes(ctx)
+			return len(sizes) == 3 && sizes[0].Size == 53
+		}, 5*time.Second, 20*time.Millisecond)
+		<-changed
+
+		versioned, err := repo.GetVersionedPackSizes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), versioned.Version)
+	})
+
+	t.Run("an invalid revision keeps the previous set", func(t *testing.T) {
+		writePackSizesFile(t, path, "packs:\n  - size: 23\n  - size: 23\n")
+		time.Sleep(500 * time.Millisecond)
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Len(t, sizes, 3)
+	})
+
+	t.Run("a revision above the limits keeps the previous set", func(t *testing.T) {
+		writePackSizesFile(t, path, "packs:\n  - size: 23\n  - size: 2000000\n")
+		time.Sleep(500 * time.Millisecond)
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Len(t, sizes, 3)
+	})
+}
+
+func TestFilePackSizesRepositoryRejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pack-sizes.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"packs":[{"size":0}]}`), 0o600))
+
+	_, err := adapters.NewFilePackSizesRepository(path, domain.DefaultInputLimits)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"packs":[{"size":250},{"size":500}]}`), 0o600))
+	_, err = adapters.NewFilePackSizesRepository(path, domain.InputLimits{MaxPackSizes: 1})
+	require.ErrorIs(t, err, domain.ErrPackSizesLimit)
+}
@@ -0,0 +1,105 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// JWTConfig selects the keys and claims bearer tokens are checked against.
+// At least one of HS256Secret and RS256PublicKeyFile is required.
+type JWTConfig struct {
+	HS256Secret        string
+	RS256PublicKeyFile string
+	// Issuer and Audience are checked only when set.
+	Issuer   string
+	Audience string
+}
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// JWTAuthenticator accepts bearer tokens signed with a local HS256 secret or
+// RS256 public key. The sub claim names the caller and roles lists its
+// roles; tokens must expire.
+type JWTAuthenticator struct {
+	hs256Secret []byte
+	rs256Key    any
+	parser      *jwt.Parser
+}
+
+func NewJWTAuthenticator(cfg JWTConfig) (*JWTAuthenticator, error) {
+	a := &JWTAuthenticator{}
+	var methods []string
+
+	if cfg.HS256Secret != "" {
+		a.hs256Secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.RS256PublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.RS256PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading jwt public key: %w", err)
+		}
+		a.rs256Key, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("parsing jwt public key: %w", err)
+		}
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no jwt key configured")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+// Authenticate returns nil when r has no bearer token. Roles this service
+// does not know are ignored.
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*domain.Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	var claims jwtClaims
+	if _, err := a.parser.ParseWithClaims(token, &claims, a.key); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrUnauthenticated, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", domain.ErrUnauthenticated)
+	}
+
+	p := &domain.Principal{Subject: claims.Subject}
+	for _, name := range claims.Roles {
+		if role, ok := domain.ParseRole(name); ok {
+			p.Roles = append(p.Roles, role)
+		}
+	}
+	return p, nil
+}
+
+// key picks the key for the token's algorithm; WithValidMethods has already
+// rejected algorithms without one.
+func (a *JWTAuthenticator) key(token *jwt.Token) (any, error) {
+	if token.Method == jwt.SigningMethodRS256 {
+		return a.rs256Key, nil
+	}
+	return a.hs256Secret, nil
+}
@@ -0,0 +1,127 @@
+package adapters_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	const secret = "0123456789abcdef0123456789abcdef"
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "jwt.pub")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	a, err := adapters.NewJWTAuthenticator(adapters.JWTConfig{
+		HS256Secret:        secret,
+		RS256PublicKeyFile: keyFile,
+		Issuer:             "https://auth.example.com",
+		Audience:           "smart-pack",
+	})
+	require.NoError(t, err)
+
+	claims := func(modify func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub":   "alice",
+			"iss":   "https://auth.example.com",
+			"aud":   "smart-pack",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"roles": []string{"calculator", "unknown"},
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+	hs256 := func(c jwt.MapClaims) string { return mustSign(t, jwt.SigningMethodHS256, c, []byte(secret)) }
+	rs256 := func(c jwt.MapClaims) string { return mustSign(t, jwt.SigningMethodRS256, c, rsaKey) }
+	alice := &domain.Principal{Subject: "alice", Roles: []domain.Role{domain.RoleCalculator}}
+
+	testCases := []struct {
+		Name          string
+		Authorization string
+		Principal     *domain.Principal
+		Err           error
+	}{
+		{Name: "no token"},
+		{Name: "other scheme", Authorization: "Basic YWxpY2U6cHc="},
+		{Name: "hs256", Authorization: "Bearer " + hs256(claims(nil)), Principal: alice},
+		{Name: "rs256", Authorization: "bearer " + rs256(claims(nil)), Principal: alice},
+		{
+			Name:          "wrong secret",
+			Authorization: "Bearer " + mustSign(t, jwt.SigningMethodHS256, claims(nil), []byte("another secret")),
+			Err:           domain.ErrUnauthenticated,
+		},
+		{
+			Name:          "expired",
+			Authorization: "Bearer " + hs256(claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() })),
+			Err:           domain.ErrUnauthenticated,
+		},
+		{
+			Name:          "no expiry",
+			Authorization: "Bearer " + hs256(claims(func(c jwt.MapClaims) { delete(c, "exp") })),
+			Err:           domain.ErrUnauthenticated,
+		},
+		{
+			Name:          "wrong issuer",
+			Authorization: "Bearer " + hs256(claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" })),
+			Err:           domain.ErrUnauthenticated,
+		},
+		{
+			Name:          "wrong audience",
+			Authorization: "Bearer " + hs256(claims(func(c jwt.MapClaims) { c["aud"] = "billing" })),
+			Err:           domain.ErrUnauthenticated,
+		},
+		{
+			Name:          "no subject",
+			Authorization: "Bearer " + hs256(claims(func(c jwt.MapClaims) { delete(c, "sub") })),
+			Err:           domain.ErrUnauthenticated,
+		},
+		{
+			Name:          "unsigned",
+			Authorization: "Bearer " + mustSign(t, jwt.SigningMethodNone, claims(nil), jwt.UnsafeAllowNoneSignatureType),
+			Err:           domain.ErrUnauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/pack-sizes", nil)
+			if tc.Authorization != "" {
+				r.Header.Set("Authorization", tc.Authorization)
+			}
+
+			p, err := a.Authenticate(r)
+			require.ErrorIs(t, err, tc.Err)
+			require.Equal(t, tc.Principal, p)
+		})
+	}
+}
+
+func TestNewJWTAuthenticatorRequiresAKey(t *testing.T) {
+	_, err := adapters.NewJWTAuthenticator(adapters.JWTConfig{Issuer: "https://auth.example.com"})
+	require.Error(t, err)
+}
+
+func mustSign(t *testing.T, method jwt.SigningMethod, c jwt.Claims, key any) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(method, c).SignedString(key)
+	require.NoError(t, err)
+	return s
+}
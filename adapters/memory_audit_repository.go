@@ -0,0 +1,70 @@
+package adapters
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// MemoryAuditRepository keeps the audit log in a MemoryStore.
+type MemoryAuditRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryAuditRepository(store *MemoryStore) *MemoryAuditRepository {
+	return &MemoryAuditRepository{store: store}
+}
+
+func (r *MemoryAuditRepository) AppendAuditEntry(ctx context.Context, entry domain.AuditEntry) error {
+	defer r.store.lock(ctx)()
+
+	r.store.nextAuditID++
+	entry.ID = r.store.nextAuditID
+	entry.Before = slices.Clone(entry.Before)
+	entry.After = slices.Clone(entry.After)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	r.store.audit = append(r.store.audit, entry)
+	return nil
+}
+
+func (r *MemoryAuditRepository) ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	defer r.store.lock(ctx)()
+
+	entries := make([]domain.AuditEntry, 0)
+	skipped := 0
+	for i := len(r.store.audit) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(entries) >= filter.Limit {
+			break
+		}
+		e := r.store.audit[i]
+		if !matchesAuditFilter(e, filter) {
+			continue
+		}
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func matchesAuditFilter(e domain.AuditEntry, filter domain.AuditFilter) bool {
+	switch {
+	case filter.Action != "" && e.Action != filter.Action:
+		return false
+	case filter.Actor != "" && e.Actor != filter.Actor:
+		return false
+	case filter.RequestID != "" && e.RequestID != filter.RequestID:
+		return false
+	case filter.From != nil && e.CreatedAt.Before(*filter.From):
+		return false
+	case filter.To != nil && e.CreatedAt.After(*filter.To):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package adapters
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// MemoryOutboxRepository keeps the outbox in a MemoryStore.
+type MemoryOutboxRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryOutboxRepository(store *MemoryStore) *MemoryOutboxRepository {
+	return &MemoryOutboxRepository{store: store}
+}
+
+func (r *MemoryOutboxRepository) AppendOutboxEvent(ctx context.Context, event domain.OutboxEvent) error {
+	defer r.store.lock(ctx)()
+
+	r.store.nextOutboxID++
+	event.ID = r.store.nextOutboxID
+	event.Payload = slices.Clone(event.Payload)
+	r.store.outbox = append(r.store.outbox, event)
+	return nil
+}
+
+func (r *MemoryOutboxRepository) ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error) {
+	defer r.store.lock(ctx)()
+
+	events := make([]domain.OutboxEvent, 0)
+	for i := range r.store.outbox {
+		if len(events) >= limit {
+			break
+		}
+		e := &r.store.outbox[i]
+		if e.PublishedAt != nil || e.NextAttemptAt.After(now) {
+			continue
+		}
+		e.NextAttemptAt = leaseUntil
+		events = append(events, *e)
+	}
+	return events, nil
+}
+
+func (r *MemoryOutboxRepository) MarkOutboxEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	defer r.store.lock(ctx)()
+
+	if e := r.find(id); e != nil {
+		e.Attempts++
+		e.LastError = ""
+		e.PublishedAt = &publishedAt
+	}
+	return nil
+}
+
+func (r *MemoryOutboxRepository) MarkOutboxEventFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	defer r.store.lock(ctx)()
+
+	if e := r.find(id); e != nil {
+		e.Attempts++
+		e.LastError = lastError
+		e.NextAttemptAt = nextAttemptAt
+	}
+	return nil
+}
+
+func (r *MemoryOutboxRepository) find(id int64) *domain.OutboxEvent {
+	for i := range r.store.outbox {
+		if r.store.outbox[i].ID == id {
+			return &r.store.outbox[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/tests/contract"
+)
+
+func TestMemoryOutboxRepository(t *testing.T) {
+	contract.RunOutboxRepository(t, func(t *testing.T) contract.OutboxRepository {
+		return adapters.NewMemoryOutboxRepository(adapters.NewMemoryStore())
+	})
+}
@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// rateLimitSweepInterval is how often full buckets, which hold nothing a new
+// bucket would not, are dropped.
+const rateLimitSweepInterval = time.Minute
+
+// MemoryRateLimiter keeps rate-limit buckets in process memory, so each
+// replica enforces the limits on its own.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]domain.RateLimitBucket
+	fullAt    map[string]time.Time
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets: map[string]domain.RateLimitBucket{},
+		fullAt:  map[string]time.Time{},
+		now:     time.Now,
+	}
+}
+
+func (l *MemoryRateLimiter) Take(_ context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = domain.NewRateLimitBucket(limit, now)
+	}
+	b, d := b.Take(limit, now)
+	l.buckets[key] = b
+	l.fullAt[key] = b.FullAt(limit)
+	return d, nil
+}
+
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+	l.nextSweep = now.Add(rateLimitSweepInterval)
+	for key, fullAt := range l.fullAt {
+		if !fullAt.After(now) {
+			delete(l.buckets, key)
+			delete(l.fullAt, key)
+		}
+	}
+}
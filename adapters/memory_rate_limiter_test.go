@@ -0,0 +1,15 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/ports/rest"
+	"github.com/rossi1/smart-pack/tests/contract"
+)
+
+func TestMemoryRateLimiterContract(t *testing.T) {
+	contract.RunRateLimiter(t, func(t *testing.T) rest.RateLimiter {
+		return adapters.NewMemoryRateLimiter()
+	})
+}
@@ -0,0 +1,120 @@
+package adapters
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// MemorySmartPackRepository stores pack sizes in a MemoryStore with the same
+// soft-delete semantics as SmartPackRepository: replaced sizes are kept with
+// DeletedAt set rather than removed.
+type MemorySmartPackRepository struct {
+	store *MemoryStore
+}
+
+func NewMemorySmartPackRepository(store *MemoryStore) *MemorySmartPackRepository {
+	return &MemorySmartPackRepository{store: store}
+}
+
+func (r *MemorySmartPackRepository) GetPackSizes(ctx context.Context) ([]domain.SmartPack, error) {
+	defer r.store.lock(ctx)()
+
+	return r.activePackSizes(), nil
+}
+
+// GetVersionedPackSizes versions the active set by its highest pack id, like
+// SmartPackRepository.
+func (r *MemorySmartPackRepository) GetVersionedPackSizes(ctx context.Context) (domain.VersionedPackSizes, error) {
+	defer r.store.lock(ctx)()
+
+	versioned := domain.VersionedPackSizes{Packs: r.activePackSizes()}
+	for _, e := range r.store.packs {
+		if e.DeletedAt == nil {
+			versioned.Version = max(versioned.Version, int64(e.ID))
+		}
+	}
+	return versioned, nil
+}
+
+func (r *MemorySmartPackRepository) SetPackSizes(ctx context.Context, sizes []domain.SmartPack) ([]domain.SmartPack, error) {
+	defer r.store.lock(ctx)()
+
+	previous := r.activePackSizes()
+
+	now := time.Now()
+	for i := range r.store.packs {
+		if r.store.packs[i].DeletedAt == nil {
+			r.store.packs[i].DeletedAt = &now
+		}
+	}
+
+	for _, size := range sizes {
+		r.store.nextPackID++
+		r.store.packs = append(r.store.packs, SmartPackEntity{
+			ID:        r.store.nextPackID,
+			Size:      size.Size,
+			Label:     size.Label,
+			SKU:       size.SKU,
+			GTIN:      size.GTIN,
+			Enabled:   size.Enabled,
+			CreatedAt: now,
+		})
+	}
+
+	return previous, nil
+}
+
+func (r *MemorySmartPackRepository) ListRetiredGenerations(ctx context.Context) ([]domain.PackSizeGeneration, error) {
+	defer r.store.lock(ctx)()
+
+	counts := make(map[time.Time]int)
+	for _, e := range r.store.packs {
+		if e.DeletedAt != nil {
+			counts[*e.DeletedAt]++
+		}
+	}
+
+	generations := make([]domain.PackSizeGeneration, 0, len(counts))
+	for retiredAt, packs := range counts {
+		generations = append(generations, domain.PackSizeGeneration{RetiredAt: retiredAt, Packs: packs})
+	}
+	sort.Slice(generations, func(i, j int) bool {
+		return generations[i].RetiredAt.After(generations[j].RetiredAt)
+	})
+	return generations, nil
+}
+
+// PurgeGenerations drops the packs retired at the given times. There is no
+// archive in memory, so archive is ignored.
+func (r *MemorySmartPackRepository) PurgeGenerations(ctx context.Context, retiredAt []time.Time, archive bool) (int, error) {
+	defer r.store.lock(ctx)()
+
+	purge := make(map[time.Time]bool, len(retiredAt))
+	for _, t := range retiredAt {
+		purge[t] = true
+	}
+
+	kept := r.store.packs[:0]
+	for _, e := range r.store.packs {
+		if e.DeletedAt == nil || !purge[*e.DeletedAt] {
+			kept = append(kept, e)
+		}
+	}
+	purged := len(r.store.packs) - len(kept)
+	r.store.packs = kept
+	return purged, nil
+}
+
+func (r *MemorySmartPackRepository) activePackSizes() []domain.SmartPack {
+	var sizes []domain.SmartPack
+	for _, e := range r.store.packs {
+		if e.DeletedAt == nil {
+			sizes = append(sizes, mapSmartPackEntityToDomain(e))
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].Size > sizes[j].Size })
+	return sizes
+}
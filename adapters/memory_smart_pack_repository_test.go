@@ -0,0 +1,51 @@
+package adapters_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/tests/contract"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySmartPackRepository(t *testing.T) {
+	contract.RunPackSizesRepository(t, func(t *testing.T) contract.PackSizesRepository {
+		return adapters.NewMemorySmartPackRepository(adapters.NewMemoryStore())
+	})
+}
+
+func TestMemoryTransactorRollsBack(t *testing.T) {
+	store := adapters.NewMemoryStore()
+	repo := adapters.NewMemorySmartPackRepository(store)
+	audit := adapters.NewMemoryAuditRepository(store)
+	ctx := context.Background()
+
+	before, err := repo.GetPackSizes(ctx)
+	require.NoError(t, err)
+
+	errAudit := errors.New("audit failed")
+	err = adapters.NewMemoryTransactor(store).WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.SetPackSizes(ctx, []domain.SmartPack{{Size: 42, Enabled: true}})
+		require.NoError(t, err)
+		require.NoError(t, audit.AppendAuditEntry(ctx, domain.AuditEntry{Action: domain.AuditActionSetPackSizes}))
+		return errAudit
+	})
+	require.ErrorIs(t, err, errAudit)
+
+	after, err := repo.GetPackSizes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+
+	entries, err := audit.ListAuditEntries(ctx, domain.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestMemorySmartPackRepositoryRetention(t *testing.T) {
+	contract.RunRetentionRepository(t, func(t *testing.T) contract.RetentionRepository {
+		return adapters.NewMemorySmartPackRepository(adapters.NewMemoryStore())
+	})
+}
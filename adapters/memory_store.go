@@ -0,0 +1,124 @@
+package adapters
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// defaultPackSizes mirrors the rows seeded by the first migration.
+var defaultPackSizes = []int{250, 500, 1000, 2000, 5000}
+
+// MemoryStore keeps pack sizes, the audit log, the outbox and webhooks in
+// process memory. It is meant for local development and demos; nothing
+// survives a restart.
+type MemoryStore struct {
+	mu             sync.Mutex
+	packs          []SmartPackEntity
+	audit          []domain.AuditEntry
+	outbox         []domain.OutboxEvent
+	webhooks       []domain.WebhookSubscription
+	deliveries     []domain.WebhookDelivery
+	nextPackID     int
+	nextAuditID    int64
+	nextOutboxID   int64
+	nextWebhookID  int64
+	nextDeliveryID int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{}
+	now := time.Now()
+	for _, size := range defaultPackSizes {
+		s.nextPackID++
+		s.packs = append(s.packs, SmartPackEntity{
+			ID:        s.nextPackID,
+			Size:      size,
+			Enabled:   true,
+			CreatedAt: now,
+		})
+	}
+	return s
+}
+
+type memoryTxKey struct{}
+
+// lock acquires the store unless ctx already runs inside a MemoryTransactor
+// transaction, which holds the lock for its whole duration.
+func (s *MemoryStore) lock(ctx context.Context) func() {
+	if held, _ := ctx.Value(memoryTxKey{}).(*MemoryStore); held == s {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+type memorySnapshot struct {
+	packs          []SmartPackEntity
+	audit          []domain.AuditEntry
+	outbox         []domain.OutboxEvent
+	webhooks       []domain.WebhookSubscription
+	deliveries     []domain.WebhookDelivery
+	nextPackID     int
+	nextAuditID    int64
+	nextOutboxID   int64
+	nextWebhookID  int64
+	nextDeliveryID int64
+}
+
+func (s *MemoryStore) snapshot() memorySnapshot {
+	return memorySnapshot{
+		packs:          slices.Clone(s.packs),
+		audit:          slices.Clone(s.audit),
+		outbox:         slices.Clone(s.outbox),
+		webhooks:       slices.Clone(s.webhooks),
+		deliveries:     slices.Clone(s.deliveries),
+		nextPackID:     s.nextPackID,
+		nextAuditID:    s.nextAuditID,
+		nextOutboxID:   s.nextOutboxID,
+		nextWebhookID:  s.nextWebhookID,
+		nextDeliveryID: s.nextDeliveryID,
+	}
+}
+
+func (s *MemoryStore) restore(snap memorySnapshot) {
+	s.packs = snap.packs
+	s.audit = snap.audit
+	s.outbox = snap.outbox
+	s.webhooks = snap.webhooks
+	s.deliveries = snap.deliveries
+	s.nextPackID = snap.nextPackID
+	s.nextAuditID = snap.nextAuditID
+	s.nextOutboxID = snap.nextOutboxID
+	s.nextWebhookID = snap.nextWebhookID
+	s.nextDeliveryID = snap.nextDeliveryID
+}
+
+// MemoryTransactor is the in-memory counterpart of PostgresTransactor.
+// Transactions are serialised and undone when fn returns an error.
+type MemoryTransactor struct {
+	store *MemoryStore
+}
+
+func NewMemoryTransactor(store *MemoryStore) *MemoryTransactor {
+	return &MemoryTransactor{store: store}
+}
+
+func (t *MemoryTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if held, _ := ctx.Value(memoryTxKey{}).(*MemoryStore); held == t.store {
+		return fn(ctx)
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	snap := t.store.snapshot()
+	if err := fn(context.WithValue(ctx, memoryTxKey{}, t.store)); err != nil {
+		t.store.restore(snap)
+		return err
+	}
+	return nil
+}
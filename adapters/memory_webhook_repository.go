@@ -0,0 +1,135 @@
+package adapters
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// MemoryWebhookRepository keeps webhook subscriptions and deliveries in a
+// MemoryStore.
+type MemoryWebhookRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryWebhookRepository(store *MemoryStore) *MemoryWebhookRepository {
+	return &MemoryWebhookRepository{store: store}
+}
+
+func (r *MemoryWebhookRepository) CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	defer r.store.lock(ctx)()
+
+	r.store.nextWebhookID++
+	sub.ID = r.store.nextWebhookID
+	sub.EventTypes = slices.Clone(sub.EventTypes)
+	sub.CreatedAt = time.Now().UTC()
+	r.store.webhooks = append(r.store.webhooks, sub)
+	return sub, nil
+}
+
+func (r *MemoryWebhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	defer r.store.lock(ctx)()
+
+	return slices.Clone(r.store.webhooks), nil
+}
+
+func (r *MemoryWebhookRepository) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	defer r.store.lock(ctx)()
+
+	i := slices.IndexFunc(r.store.webhooks, func(s domain.WebhookSubscription) bool { return s.ID == id })
+	if i < 0 {
+		return domain.ErrWebhookNotFound
+	}
+	r.store.webhooks = slices.Delete(r.store.webhooks, i, i+1)
+	r.store.deliveries = slices.DeleteFunc(r.store.deliveries, func(d domain.WebhookDelivery) bool {
+		return d.SubscriptionID == id
+	})
+	return nil
+}
+
+func (r *MemoryWebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	defer r.store.lock(ctx)()
+
+	for _, d := range deliveries {
+		queued := slices.ContainsFunc(r.store.deliveries, func(q domain.WebhookDelivery) bool {
+			return q.SubscriptionID == d.SubscriptionID && q.EventID == d.EventID
+		})
+		if queued {
+			continue
+		}
+		r.store.nextDeliveryID++
+		d.ID = r.store.nextDeliveryID
+		d.Payload = slices.Clone(d.Payload)
+		r.store.deliveries = append(r.store.deliveries, d)
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	defer r.store.lock(ctx)()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for i := range r.store.deliveries {
+		if len(deliveries) >= limit {
+			break
+		}
+		d := &r.store.deliveries[i]
+		if d.Status != domain.WebhookDeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		d.NextAttemptAt = leaseUntil
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, nil
+}
+
+func (r *MemoryWebhookRepository) GetWebhookDelivery(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	defer r.store.lock(ctx)()
+
+	for _, d := range r.store.deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return domain.WebhookDelivery{}, domain.ErrWebhookDeliveryNotFound
+}
+
+func (r *MemoryWebhookRepository) UpdateWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	defer r.store.lock(ctx)()
+
+	for i := range r.store.deliveries {
+		if r.store.deliveries[i].ID == delivery.ID {
+			r.store.deliveries[i] = delivery
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWebhookRepository) ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	defer r.store.lock(ctx)()
+
+	if !slices.ContainsFunc(r.store.webhooks, func(s domain.WebhookSubscription) bool { return s.ID == filter.SubscriptionID }) {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	skipped := 0
+	for i := len(r.store.deliveries) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(deliveries) >= filter.Limit {
+			break
+		}
+		d := r.store.deliveries[i]
+		if d.SubscriptionID != filter.SubscriptionID || (filter.Status != "" && d.Status != filter.Status) {
+			continue
+		}
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
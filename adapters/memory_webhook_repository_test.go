@@ -0,0 +1,14 @@
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/tests/contract"
+)
+
+func TestMemoryWebhookRepository(t *testing.T) {
+	contract.RunWebhookRepository(t, func(t *testing.T) contract.WebhookRepository {
+		return adapters.NewMemoryWebhookRepository(adapters.NewMemoryStore())
+	})
+}
@@ -3,11 +3,25 @@ package adapters
 import (
 	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/rossi1/smart-pack/resources"
+)
+
+var (
+	ErrSchemaDirty    = errors.New("database schema is dirty")
+	ErrSchemaOutdated = errors.New("database schema is outdated")
 )
 
 type MigrationManager struct {
@@ -15,91 +29,244 @@ type MigrationManager struct {
 	migrationPath string
 }
 
+// NewMigrationManager reads migrations from migrationPath on disk, or from
+// the migrations embedded in the binary when it is empty.
 func NewMigrationManager(databaseURL, migrationPath string) *MigrationManager {
 	return &MigrationManager{databaseURL: databaseURL, migrationPath: migrationPath}
 }
 
-func (m *MigrationManager) LoadMigrations() error {
-	return m.load(m.migrationPath)
+// MigrationResult is the schema version before and after a migration. They
+// are equal when there was nothing to do.
+type MigrationResult struct {
+	From uint
+	To   uint
+}
+
+// LoadMigrations applies every pending migration.
+func (m *MigrationManager) LoadMigrations() (MigrationResult, error) {
+	return m.migrate(func(migrator *migrate.Migrate) error { return migrator.Up() })
+}
+
+// RollbackMigration reverts the last applied migration, if any.
+func (m *MigrationManager) RollbackMigration() (MigrationResult, error) {
+	return m.migrate(func(migrator *migrate.Migrate) error {
+		if _, _, err := migrator.Version(); errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
+		return migrator.Steps(-1)
+	})
+}
+
+// RollbackAllMigrations reverts every applied migration.
+func (m *MigrationManager) RollbackAllMigrations() (MigrationResult, error) {
+	return m.migrate(func(migrator *migrate.Migrate) error { return migrator.Down() })
 }
 
-func (m *MigrationManager) RollbackMigration() error {
-	return m.rollback(m.migrationPath)
+// MigrateTo migrates up or down to the given version.
+func (m *MigrationManager) MigrateTo(version uint) (MigrationResult, error) {
+	return m.migrate(func(migrator *migrate.Migrate) error { return migrator.Migrate(version) })
 }
 
 func (m *MigrationManager) GetStatus() (version uint, dirty bool, err error) {
-	return m.status(m.migrationPath)
+	migrator, err := m.newMigrator(m.migrationPath)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to initialize migrator: %w", err)
+	}
+	defer migrator.Close()
+
+	return migratorVersion(migrator)
 }
 
-func (m *MigrationManager) load(dir string) error {
-	migrator, err := m.newMigrator(dir)
+// ForceVersion records version as applied and clears the dirty flag without
+// running any migration, to recover from a failed one. -1 means no version.
+func (m *MigrationManager) ForceVersion(version int) error {
+	migrator, err := m.newMigrator(m.migrationPath)
 	if err != nil {
 		return fmt.Errorf("failed to initialize migrator: %w", err)
 	}
 	defer migrator.Close()
 
-	if err := migrator.Up(); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			log.Println("Database schema is already up to date")
-			return nil
-		}
-		return fmt.Errorf("failed to apply migrations: %w", err)
+	if err := migrator.Force(version); err != nil {
+		return fmt.Errorf("failed to force version %d: %w", version, err)
 	}
-
-	log.Println("Successfully applied all migrations")
 	return nil
 }
 
-func (m *MigrationManager) rollback(dir string) error {
-	migrator, err := m.newMigrator(dir)
-	if err != nil {
-		return fmt.Errorf("failed to initialize migrator: %w", err)
+// CreateMigration scaffolds empty up and down files in each of dirs, all
+// numbered after the highest migration in any of them so that the
+// directories stay in step, and returns their paths. Nothing is created
+// unless every directory can be read.
+func CreateMigration(name string, dirs ...string) ([]string, error) {
+	slug := migrationSlug(name)
+	if slug == "" {
+		return nil, fmt.Errorf("invalid migration name %q", name)
 	}
-	defer migrator.Close()
 
-	version, _, err := migrator.Version()
-	if err != nil {
-		if errors.Is(err, migrate.ErrNilVersion) {
-			log.Println("No migrations to roll back - database is at base version")
-			return nil
+	var next uint64 = 1
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		for _, e := range entries {
+			prefix, _, ok := strings.Cut(e.Name(), "_")
+			if e.IsDir() || !ok {
+				continue
+			}
+			if v, err := strconv.ParseUint(prefix, 10, 64); err == nil && v >= next {
+				next = v + 1
+			}
 		}
-		return fmt.Errorf("failed to check migration version: %w", err)
 	}
 
-	if err := migrator.Steps(-1); err != nil {
-		return fmt.Errorf("failed to roll back migration: %w", err)
+	files := make([]string, 0, 2*len(dirs))
+	for _, dir := range dirs {
+		base := filepath.Join(dir, fmt.Sprintf("%03d_%s", next, slug))
+		for _, path := range []string{base + ".up.sql", base + ".down.sql"} {
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if err == nil {
+				err = f.Close()
+			}
+			if err != nil {
+				for _, created := range files {
+					_ = os.Remove(created)
+				}
+				return nil, fmt.Errorf("failed to create migration: %w", err)
+			}
+			files = append(files, path)
+		}
 	}
+	return files, nil
+}
 
-	log.Printf("Successfully rolled back from version %d", version)
-	return nil
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func migrationSlug(name string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
 }
 
-func (m *MigrationManager) status(dir string) (version uint, dirty bool, err error) {
-	migrator, err := m.newMigrator(dir)
+// migrate runs fn and reports the version before and after it.
+func (m *MigrationManager) migrate(fn func(migrator *migrate.Migrate) error) (MigrationResult, error) {
+	migrator, err := m.newMigrator(m.migrationPath)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to initialize migrator: %w", err)
+		return MigrationResult{}, fmt.Errorf("failed to initialize migrator: %w", err)
 	}
 	defer migrator.Close()
 
-	ver, dirty, err := migrator.Version()
+	from, _, err := migratorVersion(migrator)
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	if err := fn(migrator); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return MigrationResult{From: from}, fmt.Errorf("failed to migrate from version %d: %w", from, err)
+	}
+	to, _, err := migratorVersion(migrator)
+	if err != nil {
+		return MigrationResult{From: from}, err
+	}
+	return MigrationResult{From: from, To: to}, nil
+}
+
+// migratorVersion is the applied version, 0 when there is none.
+func migratorVersion(migrator *migrate.Migrate) (version uint, dirty bool, err error) {
+	version, dirty, err = migrator.Version()
 	if errors.Is(err, migrate.ErrNilVersion) {
 		return 0, false, nil
 	}
 	if err != nil {
 		return 0, false, fmt.Errorf("failed to check version: %w", err)
 	}
-
-	return ver, dirty, nil
+	return version, dirty, nil
 }
 
 func (m *MigrationManager) newMigrator(dir string) (*migrate.Migrate, error) {
-	sourceURL := fmt.Sprintf("file://%s", dir)
-	databaseURL := m.databaseURL
+	src, err := m.newSource(dir)
+	if err != nil {
+		return nil, err
+	}
 
-	migrator, err := migrate.New(sourceURL, databaseURL)
+	migrator, err := migrate.NewWithSourceInstance("migrations", src, m.databaseURL)
 	if err != nil {
+		src.Close()
 		return nil, fmt.Errorf("failed to create migrator: %w", err)
 	}
 
 	return migrator, nil
 }
+
+// newSource opens dir on disk, or the embedded migrations for the database
+// when dir is empty.
+func (m *MigrationManager) newSource(dir string) (source.Driver, error) {
+	if dir != "" {
+		src, err := source.Open(fmt.Sprintf("file://%s", dir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open migrations: %w", err)
+		}
+		return src, nil
+	}
+
+	dir = resources.PostgresMigrationsDir
+	if IsSQLiteURL(m.databaseURL) {
+		dir = resources.SQLiteMigrationsDir
+	}
+
+	src, err := iofs.New(resources.Migrations, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+	return src, nil
+}
+
+// LatestVersion returns the highest migration version available.
+func (m *MigrationManager) LatestVersion() (uint, error) {
+	src, err := m.newSource(m.migrationPath)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
+
+// CheckSchema returns ErrSchemaDirty or ErrSchemaOutdated unless every
+// available migration has been applied cleanly. A schema newer than the
+// binary is accepted, as happens while replicas are rolled out.
+func (m *MigrationManager) CheckSchema() error {
+	version, dirty, err := m.GetStatus()
+	if err != nil {
+		return err
+	}
+	latest, err := m.LatestVersion()
+	if err != nil {
+		return err
+	}
+	return checkSchemaVersion(version, dirty, latest)
+}
+
+// checkSchemaVersion returns ErrSchemaDirty or ErrSchemaOutdated unless the
+// database is cleanly at latest or a later version.
+func checkSchemaVersion(version uint, dirty bool, latest uint) error {
+	if dirty {
+		return fmt.Errorf("%w: version %d failed halfway, fix it and run migrate force", ErrSchemaDirty, version)
+	}
+	if version < latest {
+		return fmt.Errorf("%w: database is at version %d, binary expects %d", ErrSchemaOutdated, version, latest)
+	}
+	return nil
+}
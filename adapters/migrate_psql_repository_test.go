@@ -0,0 +1,137 @@
+package adapters_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMigration(t *testing.T) {
+	postgres := t.TempDir()
+	sqlite := filepath.Join(postgres, "sqlite")
+	require.NoError(t, os.Mkdir(sqlite, 0o700))
+	for _, name := range []string{"001_init.up.sql", "001_init.down.sql", "007_later.up.sql", "README.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(postgres, name), nil, 0o600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(sqlite, "008_sqlite_only.up.sql"), nil, 0o600))
+
+	files, err := adapters.CreateMigration("Add Pack Weights!", postgres, sqlite)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(postgres, "009_add_pack_weights.up.sql"),
+		filepath.Join(postgres, "009_add_pack_weights.down.sql"),
+		filepath.Join(sqlite, "009_add_pack_weights.up.sql"),
+		filepath.Join(sqlite, "009_add_pack_weights.down.sql"),
+	}, files)
+	for _, f := range files {
+		require.FileExists(t, f)
+	}
+
+	_, err = adapters.CreateMigration("!!!", postgres, sqlite)
+	require.Error(t, err)
+}
+
+func TestCreateMigrationNeedsEveryDirectory(t *testing.T) {
+	postgres := t.TempDir()
+
+	_, err := adapters.CreateMigration("add_weights", postgres, filepath.Join(postgres, "sqlite"))
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(postgres)
+	require.NoError(t, err)
+	require.Empty(t, entries, "nothing is created unless every directory is kept in step")
+}
+
+func TestMigrationManagerGotoAndForce(t *testing.T) {
+	databaseURL := adapters.SQLiteScheme + filepath.Join(t.TempDir(), "smartpack.db")
+	m := adapters.NewMigrationManager(databaseURL, "../resources/db/sqlite")
+
+	result, err := m.MigrateTo(2)
+	require.NoError(t, err)
+	require.Equal(t, adapters.MigrationResult{From: 0, To: 2}, result)
+	version, dirty, err := m.GetStatus()
+	require.NoError(t, err)
+	require.Equal(t, uint(2), version)
+	require.False(t, dirty)
+
+	result, err = m.MigrateTo(2)
+	require.NoError(t, err)
+	require.Equal(t, adapters.MigrationResult{From: 2, To: 2}, result, "nothing to do")
+
+	require.NoError(t, m.ForceVersion(1))
+	version, _, err = m.GetStatus()
+	require.NoError(t, err)
+	require.Equal(t, uint(1), version)
+
+	require.NoError(t, m.ForceVersion(2))
+	result, err = m.RollbackMigration()
+	require.NoError(t, err)
+	require.Equal(t, adapters.MigrationResult{From: 2, To: 1}, result)
+
+	result, err = m.RollbackAllMigrations()
+	require.NoError(t, err)
+	require.Equal(t, adapters.MigrationResult{From: 1, To: 0}, result)
+
+	result, err = m.RollbackMigration()
+	require.NoError(t, err)
+	require.Equal(t, adapters.MigrationResult{}, result, "nothing left to roll back")
+}
+
+func TestMigrationManagerCheckSchema(t *testing.T) {
+	ctx := context.Background()
+	databaseURL := adapters.SQLiteScheme + filepath.Join(t.TempDir(), "smartpack.db")
+	m := adapters.NewMigrationManager(databaseURL, "")
+
+	latest, err := m.LatestVersion()
+	require.NoError(t, err)
+	require.Equal(t, uint(8), latest)
+
+	require.ErrorIs(t, m.CheckSchema(), adapters.ErrSchemaOutdated)
+
+	_, err = m.MigrateTo(2)
+	require.NoError(t, err)
+	require.ErrorIs(t, m.CheckSchema(), adapters.ErrSchemaOutdated)
+
+	_, err = m.LoadMigrations()
+	require.NoError(t, err)
+	require.NoError(t, m.CheckSchema())
+
+	db, err := adapters.OpenSQLite(ctx, databaseURL)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "UPDATE schema_migrations SET dirty = 1")
+	require.NoError(t, err)
+	require.ErrorIs(t, m.CheckSchema(), adapters.ErrSchemaDirty)
+}
+
+func TestSQLiteSchemaHealth(t *testing.T) {
+	ctx := context.Background()
+	databaseURL := adapters.SQLiteScheme + filepath.Join(t.TempDir(), "smartpack.db")
+	m := adapters.NewMigrationManager(databaseURL, "")
+	latest, err := m.LatestVersion()
+	require.NoError(t, err)
+
+	_, err = m.MigrateTo(2)
+	require.NoError(t, err)
+	db, err := adapters.OpenSQLite(ctx, databaseURL)
+	require.NoError(t, err)
+	defer db.Close()
+	health := adapters.NewSQLiteSchemaHealth(db, latest)
+	require.ErrorIs(t, health.CheckSchema(ctx), adapters.ErrSchemaOutdated)
+
+	_, err = m.LoadMigrations()
+	require.NoError(t, err)
+	require.NoError(t, health.CheckSchema(ctx))
+
+	_, err = db.ExecContext(ctx, "UPDATE schema_migrations SET dirty = 1")
+	require.NoError(t, err)
+	require.ErrorIs(t, health.CheckSchema(ctx), adapters.ErrSchemaDirty)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, health.CheckSchema(canceled), context.Canceled)
+}
@@ -0,0 +1,78 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rossi1/smart-pack/pkg/broker"
+)
+
+// NATSBroker is a broker.Broker on core NATS. Delivery is at most once: a
+// message that arrives while no worker is subscribed is lost.
+type NATSBroker struct {
+	conn   *nats.Conn
+	closed chan struct{}
+}
+
+func NewNATSBroker(url string) (*NATSBroker, error) {
+	closed := make(chan struct{})
+	conn, err := nats.Connect(url,
+		nats.Name("smart-pack worker"),
+		nats.ClosedHandler(func(*nats.Conn) { close(closed) }),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &NATSBroker{conn: conn, closed: closed}, nil
+}
+
+func (b *NATSBroker) Subscribe(ctx context.Context, subject, queue string, h broker.Handler) error {
+	sub, err := b.conn.QueueSubscribe(subject, queue, func(m *nats.Msg) {
+		h(ctx, broker.Message{
+			Subject: m.Subject,
+			Reply:   m.Reply,
+			Header:  natsHeader(m.Header),
+			Data:    m.Data,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	context.AfterFunc(ctx, func() { _ = sub.Drain() })
+	return nil
+}
+
+func (b *NATSBroker) Publish(ctx context.Context, msg broker.Message) error {
+	m := nats.NewMsg(msg.Subject)
+	m.Reply = msg.Reply
+	m.Data = msg.Data
+	for k, v := range msg.Header {
+		m.Header.Set(k, v)
+	}
+	return b.conn.PublishMsg(m)
+}
+
+// Close drains the connection: subscriptions stop receiving, handlers in
+// flight finish and their replies are flushed before it returns.
+func (b *NATSBroker) Close() error {
+	if err := b.conn.Drain(); err != nil {
+		if errors.Is(err, nats.ErrConnectionClosed) {
+			return nil
+		}
+		return err
+	}
+	<-b.closed
+	return nil
+}
+
+func natsHeader(h nats.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	header := make(map[string]string, len(h))
+	for k := range h {
+		header[k] = h.Get(k)
+	}
+	return header
+}
@@ -0,0 +1,107 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// Headers sent with every published event. EventIDHeader is stable across
+// retries so consumers can drop duplicates.
+const (
+	EventIDHeader   = "X-Event-Id"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// WebhookPublisher POSTs the event payload as JSON. Any status outside 2xx
+// counts as a failed attempt.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatInt(event.ID, 10))
+	req.Header.Set(EventTypeHeader, event.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// NATSPublisher publishes events on a NATS subject. The event ID is also
+// sent as Nats-Msg-Id, which JetStream uses to discard duplicates.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func NewNATSPublisher(url, subject string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("smart-pack outbox relay"))
+	if err != nil {
+		return nil, err
+	}
+	return &NATSPublisher{conn: conn, subject: subject}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	msg := nats.NewMsg(p.subject)
+	msg.Data = event.Payload
+	msg.Header.Set(nats.MsgIdHdr, strconv.FormatInt(event.ID, 10))
+	msg.Header.Set(EventTypeHeader, event.Type)
+
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	// Publish only buffers; a flush confirms the server received it.
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
+
+// LogPublisher writes events to the log instead of delivering them. It is
+// meant for local development and tests.
+type LogPublisher struct {
+	logger logrus.FieldLogger
+}
+
+func NewLogPublisher(logger logrus.FieldLogger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	p.logger.WithFields(logrus.Fields{
+		"event_id":   event.ID,
+		"event_type": event.Type,
+		"payload":    string(event.Payload),
+	}).Info("Outbox event published")
+	return nil
+}
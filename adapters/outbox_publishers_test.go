@@ -0,0 +1,48 @@
+package adapters_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPublisher(t *testing.T) {
+	event := domain.OutboxEvent{ID: 42, Type: domain.EventPackSizesChanged, Payload: []byte(`{"after":[]}`)}
+
+	t.Run("posts the payload with the event headers", func(t *testing.T) {
+		var got *http.Request
+		var body []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+
+		publisher := adapters.NewWebhookPublisher(srv.URL, time.Second)
+		require.NoError(t, publisher.Publish(context.Background(), event))
+
+		require.Equal(t, http.MethodPost, got.Method)
+		require.Equal(t, "application/json", got.Header.Get("Content-Type"))
+		require.Equal(t, "42", got.Header.Get(adapters.EventIDHeader))
+		require.Equal(t, domain.EventPackSizesChanged, got.Header.Get(adapters.EventTypeHeader))
+		require.JSONEq(t, string(event.Payload), string(body))
+	})
+
+	t.Run("fails on a non-2xx status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		publisher := adapters.NewWebhookPublisher(srv.URL, time.Second)
+		require.ErrorContains(t, publisher.Publish(context.Background(), event), "503")
+	})
+}
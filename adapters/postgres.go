@@ -0,0 +1,181 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// DBTX is implemented by both *pgxpool.Pool and pgx.Tx, so repositories can
+// run their statements either on the pool or inside a caller's transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// conn returns the transaction bound to ctx by PostgresTransactor, or the pool.
+func conn(ctx context.Context, pool *pgxpool.Pool) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// inTransaction runs fn in the transaction bound to ctx, or in a new one when
+// there is none, so repository methods stay atomic on their own.
+func inTransaction(ctx context.Context, pool *pgxpool.Pool, fn func(tx pgx.Tx) error) (err error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(tx)
+	}
+
+	defer func() { err = mapPostgresError(err) }()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, rollback(ctx, tx))
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Postgres error codes raised when a concurrent transaction won.
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// mapPostgresError reports a write that lost a race as domain.ErrConflict,
+// keeping the original error in the chain. Constraint violations are not
+// races and retrying them fails the same way; they are left to the
+// repository that knows what the constraint means.
+func mapPostgresError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	if _, ok := domain.IsHTTPCustomError(err); ok {
+		return err
+	}
+	switch pgErr.Code {
+	case pgSerializationFailure, pgDeadlockDetected:
+		return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	}
+	return err
+}
+
+func rollback(ctx context.Context, tx pgx.Tx) error {
+	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return err
+	}
+	return nil
+}
+
+// WithAdvisoryLock runs fn while holding the session-level advisory lock key,
+// so across all replicas only one runs fn at a time; the others wait.
+func WithAdvisoryLock(ctx context.Context, pool *pgxpool.Pool, key int64, fn func() error) (err error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		return err
+	}
+	defer func() {
+		if _, unlockErr := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", key); unlockErr != nil {
+			err = errors.Join(err, unlockErr)
+		}
+	}()
+
+	return fn()
+}
+
+// PostgresTransactor runs command handlers in a single database transaction.
+type PostgresTransactor struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTransactor(pool *pgxpool.Pool) *PostgresTransactor {
+	return &PostgresTransactor{pool: pool}
+}
+
+// WithinTransaction commits when fn succeeds and rolls back otherwise. Nested
+// calls join the outer transaction.
+func (t *PostgresTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return inTransaction(ctx, t.pool, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// PostgresHealth reports the reachability and connection usage of the pool.
+type PostgresHealth struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresHealth(pool *pgxpool.Pool) *PostgresHealth {
+	return &PostgresHealth{pool: pool}
+}
+
+func (h *PostgresHealth) Ping(ctx context.Context) error {
+	return h.pool.Ping(ctx)
+}
+
+func (h *PostgresHealth) Stats() domain.DatabaseStats {
+	s := h.pool.Stat()
+	return domain.DatabaseStats{
+		MaxConns:             s.MaxConns(),
+		TotalConns:           s.TotalConns(),
+		IdleConns:            s.IdleConns(),
+		AcquiredConns:        s.AcquiredConns(),
+		ConstructingConns:    s.ConstructingConns(),
+		AcquireCount:         s.AcquireCount(),
+		EmptyAcquireCount:    s.EmptyAcquireCount(),
+		CanceledAcquireCount: s.CanceledAcquireCount(),
+		AcquireDuration:      s.AcquireDuration(),
+	}
+}
+
+// PostgresSchemaHealth checks the version golang-migrate recorded against
+// latest, through the pool rather than a migrator of its own, which would
+// open another connection and wait for the migration lock.
+type PostgresSchemaHealth struct {
+	pool   *pgxpool.Pool
+	latest uint
+}
+
+func NewPostgresSchemaHealth(pool *pgxpool.Pool, latest uint) *PostgresSchemaHealth {
+	return &PostgresSchemaHealth{pool: pool, latest: latest}
+}
+
+func (h *PostgresSchemaHealth) CheckSchema(ctx context.Context) error {
+	var version int64
+	var dirty bool
+	err := h.pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return checkSchemaVersion(0, false, h.latest)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return checkSchemaVersion(uint(version), dirty, h.latest)
+}
@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	listenerMinBackoff = 100 * time.Millisecond
+	listenerMaxBackoff = 30 * time.Second
+)
+
+// PostgresListener LISTENs on a channel over a dedicated connection and calls
+// its subscribers for every notification. Subscribers are also called after
+// each (re)connect, because notifications sent while disconnected are lost.
+type PostgresListener struct {
+	config  *pgx.ConnConfig
+	channel string
+
+	mu          sync.Mutex
+	subscribers []func()
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPostgresListener connects with the pool's settings but outside the
+// pool, so the long-lived LISTEN connection never serves queries.
+func NewPostgresListener(pool *pgxpool.Pool, channel string) *PostgresListener {
+	return &PostgresListener{
+		config:  pool.Config().ConnConfig.Copy(),
+		channel: channel,
+	}
+}
+
+// Subscribe registers fn to be called on every notification.
+func (l *PostgresListener) Subscribe(fn func()) {
+	l.mu.Lock()
+	l.subscribers = append(l.subscribers, fn)
+	l.mu.Unlock()
+}
+
+// Start listens in the background until ctx is done or Close is called.
+func (l *PostgresListener) Start(ctx context.Context) {
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.done = make(chan struct{})
+
+	go func() {
+		defer close(l.done)
+		l.run(ctx)
+	}()
+}
+
+func (l *PostgresListener) Close() error {
+	if l.cancel != nil {
+		l.cancel()
+		<-l.done
+	}
+	return nil
+}
+
+func (l *PostgresListener) run(ctx context.Context) {
+	backoff := listenerMinBackoff
+	for {
+		err := l.listen(ctx, func() { backoff = listenerMinBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+
+		logrus.WithError(err).WithField("channel", l.channel).
+			Warnf("Postgres listener disconnected, reconnecting in %s", backoff)
+		l.notify()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, listenerMaxBackoff)
+	}
+}
+
+func (l *PostgresListener) listen(ctx context.Context, connected func()) error {
+	conn, err := pgx.ConnectConfig(ctx, l.config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return err
+	}
+	connected()
+	l.notify()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		l.notify()
+	}
+}
+
+func (l *PostgresListener) notify() {
+	l.mu.Lock()
+	subscribers := l.subscribers
+	l.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn()
+	}
+}
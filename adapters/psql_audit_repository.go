@@ -0,0 +1,186 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type AuditEntryEntity struct {
+	ID           int64     `pg:"id,pk,auto_increment"`
+	Action       string    `pg:"action,notnull"`
+	Actor        string    `pg:"actor,notnull"`
+	ClaimedActor string    `pg:"claimed_actor,notnull"`
+	SourceIP     string    `pg:"source_ip,notnull"`
+	RequestID    string    `pg:"request_id,notnull"`
+	BeforeSizes  []byte    `pg:"before_sizes,type:jsonb"`
+	AfterSizes   []byte    `pg:"after_sizes,type:jsonb"`
+	CreatedAt    time.Time `pg:"created_at,default:now()"`
+}
+
+// auditPackEntity is the JSON shape of a pack stored in the before/after columns.
+type auditPackEntity struct {
+	Size    int    `json:"size"`
+	Label   string `json:"label,omitempty"`
+	SKU     string `json:"sku,omitempty"`
+	GTIN    string `json:"gtin,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"` // nil for entries recorded before packs could be disabled
+}
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) AppendAuditEntry(ctx context.Context, entry domain.AuditEntry) error {
+	before, err := marshalAuditPacks(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalAuditPacks(entry.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).Exec(ctx,
+		`INSERT INTO audit_log (action, actor, claimed_actor, source_ip, request_id, before_sizes, after_sizes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		entry.Action, entry.Actor, entry.ClaimedActor, entry.SourceIP, entry.RequestID, before, after,
+	)
+	return err
+}
+
+func (r *AuditRepository) ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	where, args := auditFilterClause(filter, postgresPlaceholder)
+	args = append(args, filter.Limit, filter.Offset)
+
+	sql := fmt.Sprintf(
+		`SELECT id, action, actor, claimed_actor, source_ip, request_id, before_sizes, after_sizes, created_at
+		FROM audit_log %s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+		where, len(args)-1, len(args),
+	)
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		var e AuditEntryEntity
+		if err := rows.Scan(&e.ID, &e.Action, &e.Actor, &e.ClaimedActor, &e.SourceIP, &e.RequestID,
+			&e.BeforeSizes, &e.AfterSizes, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry, err := mapAuditEntityToDomain(e)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func postgresPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// auditFilterClause builds the WHERE clause for filter; placeholder renders
+// the n-th bind parameter in the driver's syntax.
+func auditFilterClause(filter domain.AuditFilter, placeholder func(n int) string) (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, condition+" "+placeholder(len(args)))
+	}
+
+	if filter.Action != "" {
+		add("action =", filter.Action)
+	}
+	if filter.Actor != "" {
+		add("actor =", filter.Actor)
+	}
+	if filter.RequestID != "" {
+		add("request_id =", filter.RequestID)
+	}
+	if filter.From != nil {
+		add("created_at >=", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at <=", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func marshalAuditPacks(packs []domain.SmartPack) ([]byte, error) {
+	entities := make([]auditPackEntity, 0, len(packs))
+	for _, p := range packs {
+		enabled := p.Enabled
+		entities = append(entities, auditPackEntity{
+			Size:    p.Size,
+			Label:   p.Label,
+			SKU:     p.SKU,
+			GTIN:    p.GTIN,
+			Enabled: &enabled,
+		})
+	}
+	return json.Marshal(entities)
+}
+
+func unmarshalAuditPacks(data []byte) ([]domain.SmartPack, error) {
+	var entities []auditPackEntity
+	if err := json.Unmarshal(data, &entities); err != nil {
+		return nil, err
+	}
+	packs := make([]domain.SmartPack, 0, len(entities))
+	for _, e := range entities {
+		packs = append(packs, domain.SmartPack{
+			Size:    e.Size,
+			Label:   e.Label,
+			SKU:     e.SKU,
+			GTIN:    e.GTIN,
+			Enabled: e.Enabled == nil || *e.Enabled,
+		})
+	}
+	return packs, nil
+}
+
+func mapAuditEntityToDomain(e AuditEntryEntity) (domain.AuditEntry, error) {
+	before, err := unmarshalAuditPacks(e.BeforeSizes)
+	if err != nil {
+		return domain.AuditEntry{}, err
+	}
+	after, err := unmarshalAuditPacks(e.AfterSizes)
+	if err != nil {
+		return domain.AuditEntry{}, err
+	}
+	return domain.AuditEntry{
+		ID:           e.ID,
+		Action:       e.Action,
+		Actor:        e.Actor,
+		ClaimedActor: e.ClaimedActor,
+		SourceIP:     e.SourceIP,
+		RequestID:    e.RequestID,
+		Before:       before,
+		After:        after,
+		CreatedAt:    e.CreatedAt,
+	}, nil
+}
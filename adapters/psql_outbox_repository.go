@@ -0,0 +1,78 @@
+package adapters
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) AppendOutboxEvent(ctx context.Context, event domain.OutboxEvent) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`INSERT INTO outbox (event_type, payload, created_at, next_attempt_at) VALUES ($1, $2, $3, $4)`,
+		event.Type, event.Payload, event.CreatedAt.UTC(), event.NextAttemptAt.UTC(),
+	)
+	return err
+}
+
+// ClaimOutboxEvents skips rows another relay has locked, so replicas running
+// the relay at the same time never claim the same event.
+func (r *OutboxRepository) ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`UPDATE outbox SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE published_at IS NULL AND next_attempt_at <= $1
+			ORDER BY id LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, payload, created_at, attempts, next_attempt_at, last_error`,
+		now.UTC(), leaseUntil.UTC(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]domain.OutboxEvent, 0)
+	for rows.Next() {
+		var e domain.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.Payload, &e.CreatedAt, &e.Attempts, &e.NextAttemptAt, &e.LastError); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the ORDER BY of the subquery.
+	slices.SortFunc(events, func(a, b domain.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkOutboxEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE outbox SET published_at = $2, attempts = attempts + 1, last_error = '' WHERE id = $1`,
+		id, publishedAt.UTC(),
+	)
+	return err
+}
+
+func (r *OutboxRepository) MarkOutboxEventFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE outbox SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3 WHERE id = $1`,
+		id, lastError, nextAttemptAt.UTC(),
+	)
+	return err
+}
@@ -0,0 +1,73 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// PostgresRateLimiter keeps rate-limit buckets in Postgres, so all replicas
+// share them. Buckets are timed by the database clock.
+type PostgresRateLimiter struct {
+	db *pgxpool.Pool
+
+	mu        sync.Mutex
+	nextSweep time.Time
+}
+
+func NewPostgresRateLimiter(db *pgxpool.Pool) *PostgresRateLimiter {
+	return &PostgresRateLimiter{db: db}
+}
+
+// Take locks the client's bucket for the update, so concurrent requests on
+// different replicas take tokens one after another.
+func (l *PostgresRateLimiter) Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	l.sweep(ctx)
+
+	var d domain.RateLimitDecision
+	err := inTransaction(ctx, l.db, func(tx pgx.Tx) error {
+		var b domain.RateLimitBucket
+		var now time.Time
+		err := tx.QueryRow(ctx,
+			`INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at, full_at) VALUES ($1, $2, now(), now())
+			ON CONFLICT (bucket_key) DO UPDATE SET bucket_key = EXCLUDED.bucket_key
+			RETURNING tokens, updated_at, now()`,
+			key, float64(limit.Limit),
+		).Scan(&b.Tokens, &b.UpdatedAt, &now)
+		if err != nil {
+			return err
+		}
+
+		b, d = b.Take(limit, now)
+		_, err = tx.Exec(ctx,
+			`UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3, full_at = $4 WHERE bucket_key = $1`,
+			key, b.Tokens, b.UpdatedAt, b.FullAt(limit),
+		)
+		return err
+	})
+	return d, err
+}
+
+// sweep drops full buckets at most once per rateLimitSweepInterval per
+// replica. A failure only delays the cleanup.
+func (l *PostgresRateLimiter) sweep(ctx context.Context) {
+	l.mu.Lock()
+	now := time.Now()
+	due := !now.Before(l.nextSweep)
+	if due {
+		l.nextSweep = now.Add(rateLimitSweepInterval)
+	}
+	l.mu.Unlock()
+	if !due {
+		return
+	}
+
+	if _, err := l.db.Exec(ctx, `DELETE FROM rate_limit_buckets WHERE full_at <= now()`); err != nil {
+		logrus.WithContext(ctx).WithError(err).Warn("Failed to drop full rate-limit buckets")
+	}
+}
@@ -2,74 +2,187 @@ package adapters
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rossi1/smart-pack/domain"
 )
 
 type SmartPackEntity struct {
 	ID        int        `pg:"id,pk,auto_increment"`
 	Size      int        `pg:"size,unique,notnull"`
+	Label     string     `pg:"label,notnull"`
+	SKU       string     `pg:"sku,notnull"`
+	GTIN      string     `pg:"gtin,notnull"`
+	Enabled   bool       `pg:"enabled,notnull,default:true"`
 	CreatedAt time.Time  `pg:"created_at,default:now()"`
 	DeletedAt *time.Time `pg:"deleted_at"` // pointer to allow NULL
 }
 
 type SmartPackRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewSmartPackRepository(db *pgx.Conn) *SmartPackRepository {
+func NewSmartPackRepository(db *pgxpool.Pool) *SmartPackRepository {
 	return &SmartPackRepository{db: db}
 }
 
 func (r *SmartPackRepository) GetPackSizes(ctx context.Context) ([]domain.SmartPack, error) {
-	rows, err := r.db.Query(ctx, "SELECT size FROM smartpack WHERE deleted_at IS NULL ORDER BY size DESC")
+	rows, err := conn(ctx, r.db).Query(ctx,
+		"SELECT size, label, sku, gtin, enabled FROM smartpack WHERE deleted_at IS NULL ORDER BY size DESC")
 	if err != nil {
 		return nil, err
 	}
+	return scanSmartPacks(rows)
+}
+
+// GetVersionedPackSizes reads the active set in a single statement. Its
+// version is the highest row id, which every SetPackSizes raises.
+func (r *SmartPackRepository) GetVersionedPackSizes(ctx context.Context) (domain.VersionedPackSizes, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		"SELECT id, size, label, sku, gtin, enabled FROM smartpack WHERE deleted_at IS NULL ORDER BY size DESC")
+	if err != nil {
+		return domain.VersionedPackSizes{}, err
+	}
 	defer rows.Close()
 
-	var sizes []domain.SmartPack
+	var versioned domain.VersionedPackSizes
 	for rows.Next() {
-		var size int
-		if err := rows.Scan(&size); err != nil {
-			return nil, err
+		var e SmartPackEntity
+		if err := rows.Scan(&e.ID, &e.Size, &e.Label, &e.SKU, &e.GTIN, &e.Enabled); err != nil {
+			return domain.VersionedPackSizes{}, err
 		}
-		sizes = append(sizes, domain.SmartPack{Size: size})
+		versioned.Version = max(versioned.Version, int64(e.ID))
+		versioned.Packs = append(versioned.Packs, mapSmartPackEntityToDomain(e))
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return domain.VersionedPackSizes{}, err
 	}
-	return sizes, nil
+	return versioned, nil
 }
 
-func (r *SmartPackRepository) SetPackSizes(ctx context.Context, sizes []domain.SmartPack) error {
-	tx, err := r.db.Begin(ctx)
+func (r *SmartPackRepository) ListRetiredGenerations(ctx context.Context) ([]domain.PackSizeGeneration, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT deleted_at, COUNT(*) FROM smartpack WHERE deleted_at IS NOT NULL
+		GROUP BY deleted_at ORDER BY deleted_at DESC`)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	var generations []domain.PackSizeGeneration
+	for rows.Next() {
+		var g domain.PackSizeGeneration
+		if err := rows.Scan(&g.RetiredAt, &g.Packs); err != nil {
+			return nil, err
+		}
+		generations = append(generations, g)
 	}
-	defer func() {
+	return generations, rows.Err()
+}
+
+func (r *SmartPackRepository) PurgeGenerations(ctx context.Context, retiredAt []time.Time, archive bool) (int, error) {
+	var purged int
+	err := inTransaction(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", packSizesLockKey); err != nil {
+			return err
+		}
+
+		if archive {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO smartpack_archive (id, size, label, sku, gtin, enabled, created_at, deleted_at)
+				SELECT id, size, label, sku, gtin, enabled, created_at, deleted_at
+				FROM smartpack WHERE deleted_at = ANY($1)`, retiredAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		tag, err := tx.Exec(ctx, "DELETE FROM smartpack WHERE deleted_at = ANY($1)", retiredAt)
 		if err != nil {
-			_ = tx.Rollback(ctx)
+			return err
 		}
-	}()
+		purged = int(tag.RowsAffected())
+		return nil
+	})
+	return purged, err
+}
 
-	now := time.Now()
+func scanSmartPacks(rows pgx.Rows) ([]domain.SmartPack, error) {
+	defer rows.Close()
 
-	// Mark all existing packs as deleted
-	_, err = tx.Exec(ctx, "UPDATE smartpack SET deleted_at = $1 WHERE deleted_at IS NULL", now)
-	if err != nil {
-		return err
+	var sizes []domain.SmartPack
+	for rows.Next() {
+		var e SmartPackEntity
+		if err := rows.Scan(&e.Size, &e.Label, &e.SKU, &e.GTIN, &e.Enabled); err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, mapSmartPackEntityToDomain(e))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
+	return sizes, nil
+}
+
+// PackSizesChannel is notified by SetPackSizes when its transaction commits.
+const PackSizesChannel = "smartpack_changed"
+
+// packSizesLockKey serialises concurrent SetPackSizes calls so that each
+// replaces exactly the generation the previous call committed.
+const packSizesLockKey = 0x736d61727470 // "smartp"
+
+func (r *SmartPackRepository) SetPackSizes(ctx context.Context, sizes []domain.SmartPack) ([]domain.SmartPack, error) {
+	var previous []domain.SmartPack
+	err := inTransaction(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", packSizesLockKey); err != nil {
+			return err
+		}
 
-	// Insert new pack sizes
-	for _, size := range sizes {
-		_, err = tx.Exec(ctx, "INSERT INTO smartpack (size) VALUES ($1)", size.Size)
+		now := time.Now()
+
+		// Mark all existing packs as deleted
+		rows, err := tx.Query(ctx,
+			`UPDATE smartpack SET deleted_at = $1 WHERE deleted_at IS NULL
+			RETURNING size, label, sku, gtin, enabled`, now)
+		if err != nil {
+			return err
+		}
+		previous, err = scanSmartPacks(rows)
 		if err != nil {
 			return err
 		}
+
+		// Insert new pack sizes
+		for _, size := range sizes {
+			_, err = tx.Exec(ctx,
+				"INSERT INTO smartpack (size, label, sku, gtin, enabled) VALUES ($1, $2, $3, $4, $5)",
+				size.Size, size.Label, size.SKU, size.GTIN, size.Enabled)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Delivered on commit only, so listeners never see a rolled back change
+		_, err = tx.Exec(ctx, "SELECT pg_notify($1, '')", PackSizesChannel)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return tx.Commit(ctx)
+	sort.Slice(previous, func(i, j int) bool { return previous[i].Size > previous[j].Size })
+	return previous, nil
+}
+
+func mapSmartPackEntityToDomain(e SmartPackEntity) domain.SmartPack {
+	return domain.SmartPack{
+		Size:    e.Size,
+		Label:   e.Label,
+		SKU:     e.SKU,
+		GTIN:    e.GTIN,
+		Enabled: e.Enabled,
+	}
 }
@@ -0,0 +1,187 @@
+package adapters
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_error, response_status, created_at, delivered_at`
+
+type WebhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	sub.CreatedAt = time.Now().UTC()
+	err = conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO webhook_subscription (url, event_types, secret, created_at) VALUES ($1, $2, $3, $4) RETURNING id`,
+		sub.URL, eventTypes, sub.Secret, sub.CreatedAt,
+	).Scan(&sub.ID)
+	return sub, err
+}
+
+func (r *WebhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT id, url, event_types, secret, created_at FROM webhook_subscription ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make([]domain.WebhookSubscription, 0)
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		var eventTypes []byte
+		if err := rows.Scan(&sub.ID, &sub.URL, &eventTypes, &sub.Secret, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(eventTypes, &sub.EventTypes); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (r *WebhookRepository) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	tag, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM webhook_subscription WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	return inTransaction(ctx, r.db, func(tx pgx.Tx) error {
+		for _, d := range deliveries {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+				d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.Status, d.NextAttemptAt.UTC(), d.CreatedAt.UTC(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClaimWebhookDeliveries skips rows another worker has locked, so replicas
+// never send the same delivery at the same time.
+func (r *WebhookRepository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`UPDATE webhook_delivery SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_delivery
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY id LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns,
+		now.UTC(), leaseUntil.UTC(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the ORDER BY of the subquery.
+	slices.SortFunc(deliveries, func(a, b domain.WebhookDelivery) int { return cmp.Compare(a.ID, b.ID) })
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) GetWebhookDelivery(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT `+webhookDeliveryColumns+` FROM webhook_delivery WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if len(deliveries) == 0 {
+		return domain.WebhookDelivery{}, domain.ErrWebhookDeliveryNotFound
+	}
+	return deliveries[0], nil
+}
+
+func (r *WebhookRepository) UpdateWebhookDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE webhook_delivery SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5,
+			response_status = $6, delivered_at = $7
+		WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt.UTC(), d.LastError, d.ResponseStatus, utcOrNil(d.DeliveredAt),
+	)
+	return err
+}
+
+func (r *WebhookRepository) ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM webhook_subscription WHERE id = $1)`, filter.SubscriptionID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT `+webhookDeliveryColumns+` FROM webhook_delivery
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC LIMIT $3 OFFSET $4`,
+		filter.SubscriptionID, filter.Status, filter.Limit, filter.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookDeliveries(rows)
+}
+
+func scanWebhookDeliveries(rows pgx.Rows) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastError, &d.ResponseStatus, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
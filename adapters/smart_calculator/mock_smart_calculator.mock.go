@@ -5,6 +5,7 @@
 package smart_calculator
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,16 +36,16 @@ func (m *MockPackCalculator) EXPECT() *MockPackCalculatorMockRecorder {
 }
 
 // Calculate mocks base method.
-func (m *MockPackCalculator) Calculate(order int, packSizes []int) (*domain.PackSolution, error) {
+func (m *MockPackCalculator) Calculate(ctx context.Context, order int, packSizes []int) (*domain.PackSolution, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Calculate", order, packSizes)
+	ret := m.ctrl.Call(m, "Calculate", ctx, order, packSizes)
 	ret0, _ := ret[0].(*domain.PackSolution)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Calculate indicates an expected call of Calculate.
-func (mr *MockPackCalculatorMockRecorder) Calculate(order, packSizes interface{}) *gomock.Call {
+func (mr *MockPackCalculatorMockRecorder) Calculate(ctx, order, packSizes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Calculate", reflect.TypeOf((*MockPackCalculator)(nil).Calculate), order, packSizes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Calculate", reflect.TypeOf((*MockPackCalculator)(nil).Calculate), ctx, order, packSizes)
 }
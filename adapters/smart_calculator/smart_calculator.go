@@ -1,37 +1,80 @@
 package smart_calculator
 
 import (
-	"errors"
+	"context"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/metrics"
+	"github.com/rossi1/smart-pack/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type PackCalculator interface {
-	Calculate(order int, packSizes []int) (*domain.PackSolution, error)
+	Calculate(ctx context.Context, order int, packSizes []int) (*domain.PackSolution, error)
 }
 
+var (
+	itemsOrderedKey = attribute.Key("smartpack.calculator.items_ordered")
+	packSizesKey    = attribute.Key("smartpack.calculator.pack_sizes")
+	tableEntriesKey = attribute.Key("smartpack.calculator.table_entries")
+	totalItemsKey   = attribute.Key("smartpack.calculator.total_items")
+	totalPacksKey   = attribute.Key("smartpack.calculator.total_packs")
+)
+
+// maxCalculationInput bounds the order and the largest pack size whatever
+// limits the caller applied, so a calculation never allocates more than two
+// tables of 2*maxCalculationInput entries.
+const maxCalculationInput = 1 << 24
+
 type packCalculatorImpl struct{}
 
 func NewPackCalculator() PackCalculator {
 	return &packCalculatorImpl{}
 }
 
-func (c *packCalculatorImpl) Calculate(order int, packSizes []int) (*domain.PackSolution, error) {
+func (c *packCalculatorImpl) Calculate(ctx context.Context, order int, packSizes []int) (_ *domain.PackSolution, err error) {
+	_, span := tracing.Tracer().Start(ctx, "PackCalculator.Calculate", trace.WithAttributes(
+		itemsOrderedKey.Int(order),
+		packSizesKey.IntSlice(packSizes),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	if order <= 0 {
-		return nil, errors.New("order must be positive")
+		return nil, domain.ErrInvalidItemsOrdered
+	}
+	if order > maxCalculationInput {
+		return nil, domain.NewLimitError(domain.ErrorItemsOrderedLimitLabel, "items_ordered", maxCalculationInput, domain.UnprocessableEntity)
 	}
 	if len(packSizes) == 0 {
-		return nil, errors.New("pack sizes empty")
+		return nil, domain.ErrNoPackSizes
 	}
 
 	// Sort pack sizes descending for better pruning and consistency
 	sort.Sort(sort.Reverse(sort.IntSlice(packSizes)))
+	if packSizes[0] > maxCalculationInput {
+		return nil, domain.NewLimitError(domain.ErrorPackSizeLimitLabel, "size", maxCalculationInput, domain.UnprocessableEntity)
+	}
+
+	// The table spans the order plus the largest pack, see findOptimalPacksMemo.
+	tableEntries := order + packSizes[0] + 1
+	span.SetAttributes(tableEntriesKey.Int(tableEntries))
 
+	start := time.Now()
 	result := findOptimalPacksMemo(order, packSizes)
+	metrics.ObserveCalculation(order, tableEntries, time.Since(start))
 	if len(result.Packs) == 0 {
-		return nil, errors.New("cannot fulfill order with given pack sizes")
+		return nil, domain.ErrOrderInfeasible
 	}
 
 	// Prepare PackDetails sorted descending by size
@@ -49,6 +92,7 @@ func (c *packCalculatorImpl) Calculate(order int, packSizes []int) (*domain.Pack
 		})
 	}
 
+	span.SetAttributes(totalItemsKey.Int(result.TotalItems), totalPacksKey.Int(result.TotalPacks))
 	return &domain.PackSolution{
 		ItemsOrdered: order,
 		TotalItems:   result.TotalItems,
@@ -1,6 +1,7 @@
 package smart_calculator
 
 import (
+	"context"
 	"testing"
 
 	"github.com/rossi1/smart-pack/domain"
@@ -13,26 +14,37 @@ func TestPackCalculator_Calculate(t *testing.T) {
 		name       string
 		order      int
 		packSizes  []int
-		expectErr  bool
+		expectErr  error
 		assertFunc func(t *testing.T, solution *domain.PackSolution)
 	}{
 		{
 			name:      "invalid order zero",
 			order:     0,
 			packSizes: []int{250, 500},
-			expectErr: true,
+			expectErr: domain.ErrInvalidItemsOrdered,
 		},
 		{
 			name:      "empty pack sizes",
 			order:     100,
 			packSizes: []int{},
-			expectErr: true,
+			expectErr: domain.ErrNoPackSizes,
+		},
+		{
+			name:      "order above the hard limit",
+			order:     maxCalculationInput + 1,
+			packSizes: []int{250, 500},
+			expectErr: domain.ErrItemsOrderedLimit,
+		},
+		{
+			name:      "pack size above the hard limit",
+			order:     100,
+			packSizes: []int{250, maxCalculationInput + 1},
+			expectErr: domain.ErrPackSizeLimit,
 		},
 		{
 			name:      "simple valid case",
 			order:     1200,
 			packSizes: []int{250, 500, 1000},
-			expectErr: false,
 			assertFunc: func(t *testing.T, solution *domain.PackSolution) {
 				require.NotNil(t, solution)
 				require.GreaterOrEqual(t, solution.TotalItems, 1200)
@@ -47,7 +59,6 @@ func TestPackCalculator_Calculate(t *testing.T) {
 			name:      "order less than smallest pack size",
 			order:     200,
 			packSizes: []int{250, 500},
-			expectErr: false,
 			assertFunc: func(t *testing.T, solution *domain.PackSolution) {
 				require.NotNil(t, solution)
 				require.GreaterOrEqual(t, solution.TotalItems, 200)
@@ -58,9 +69,9 @@ func TestPackCalculator_Calculate(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			solution, err := calculator.Calculate(tc.order, tc.packSizes)
-			if tc.expectErr {
-				require.Error(t, err)
+			solution, err := calculator.Calculate(context.Background(), tc.order, tc.packSizes)
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
 				require.Nil(t, solution)
 			} else {
 				require.NoError(t, err)
@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLiteScheme is the database URL scheme that selects the SQLite adapters,
+// e.g. sqlite://data/smartpack.db.
+const SQLiteScheme = "sqlite://"
+
+// sqliteTimeFormat sorts lexicographically and compares correctly with the
+// CURRENT_TIMESTAMP defaults in the SQLite migrations.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000"
+
+func IsSQLiteURL(databaseURL string) bool {
+	return strings.HasPrefix(databaseURL, SQLiteScheme)
+}
+
+// OpenSQLite opens the database file named by a sqlite:// URL. SQLite allows
+// a single writer, so the handle is limited to one connection.
+func OpenSQLite(ctx context.Context, databaseURL string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", strings.TrimPrefix(databaseURL, SQLiteScheme))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// sqlDBTX is implemented by both *sql.DB and *sql.Tx.
+type sqlDBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type sqliteTxKey struct{}
+
+// sqliteConn returns the transaction bound to ctx by SQLiteTransactor, or db.
+func sqliteConn(ctx context.Context, db *sql.DB) sqlDBTX {
+	if tx, ok := ctx.Value(sqliteTxKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// sqliteInTransaction is the database/sql counterpart of inTransaction.
+func sqliteInTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	if tx, ok := ctx.Value(sqliteTxKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	defer func() { err = mapSQLiteError(err) }()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// mapSQLiteError is the SQLite counterpart of mapPostgresError. A busy
+// database means another connection, possibly another process, holds the
+// write lock; constraint violations are left to the repositories.
+func mapSQLiteError(err error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+	if _, ok := domain.IsHTTPCustomError(err); ok {
+		return err
+	}
+	if sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY {
+		return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	}
+	return err
+}
+
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// SQLiteTransactor runs command handlers in a single SQLite transaction.
+type SQLiteTransactor struct {
+	db *sql.DB
+}
+
+func NewSQLiteTransactor(db *sql.DB) *SQLiteTransactor {
+	return &SQLiteTransactor{db: db}
+}
+
+func (t *SQLiteTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(sqliteTxKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	return sqliteInTransaction(ctx, t.db, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, sqliteTxKey{}, tx))
+	})
+}
+
+// SQLiteHealth reports the reachability and connection usage of the database.
+type SQLiteHealth struct {
+	db *sql.DB
+}
+
+func NewSQLiteHealth(db *sql.DB) *SQLiteHealth {
+	return &SQLiteHealth{db: db}
+}
+
+func (h *SQLiteHealth) Ping(ctx context.Context) error {
+	return h.db.PingContext(ctx)
+}
+
+func (h *SQLiteHealth) Stats() domain.DatabaseStats {
+	// database/sql does not count acquires, only those that had to wait.
+	s := h.db.Stats()
+	return domain.DatabaseStats{
+		MaxConns:          int32(s.MaxOpenConnections),
+		TotalConns:        int32(s.OpenConnections),
+		IdleConns:         int32(s.Idle),
+		AcquiredConns:     int32(s.InUse),
+		EmptyAcquireCount: s.WaitCount,
+		AcquireDuration:   s.WaitDuration,
+	}
+}
+
+// SQLiteSchemaHealth checks the version golang-migrate recorded against
+// latest, through the database rather than a migrator of its own.
+type SQLiteSchemaHealth struct {
+	db     *sql.DB
+	latest uint
+}
+
+func NewSQLiteSchemaHealth(db *sql.DB, latest uint) *SQLiteSchemaHealth {
+	return &SQLiteSchemaHealth{db: db, latest: latest}
+}
+
+func (h *SQLiteSchemaHealth) CheckSchema(ctx context.Context) error {
+	var version int64
+	var dirty bool
+	err := h.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return checkSchemaVersion(0, false, h.latest)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return checkSchemaVersion(uint(version), dirty, h.latest)
+}
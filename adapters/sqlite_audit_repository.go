@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type SQLiteAuditRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteAuditRepository(db *sql.DB) *SQLiteAuditRepository {
+	return &SQLiteAuditRepository{db: db}
+}
+
+func (r *SQLiteAuditRepository) AppendAuditEntry(ctx context.Context, entry domain.AuditEntry) error {
+	before, err := marshalAuditPacks(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalAuditPacks(entry.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = sqliteConn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO audit_log (action, actor, claimed_actor, source_ip, request_id, before_sizes, after_sizes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Action, entry.Actor, entry.ClaimedActor, entry.SourceIP, entry.RequestID,
+		string(before), string(after), sqliteTime(time.Now()),
+	)
+	return err
+}
+
+func (r *SQLiteAuditRepository) ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	where, args := auditFilterClause(filter, func(int) string { return "?" })
+	for i, arg := range args {
+		if t, ok := arg.(time.Time); ok {
+			args[i] = sqliteTime(t)
+		}
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(
+		`SELECT id, action, actor, claimed_actor, source_ip, request_id, before_sizes, after_sizes, created_at
+		FROM audit_log %s ORDER BY id DESC LIMIT ? OFFSET ?`,
+		where,
+	)
+
+	rows, err := sqliteConn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		var e AuditEntryEntity
+		var before, after string
+		if err := rows.Scan(&e.ID, &e.Action, &e.Actor, &e.ClaimedActor, &e.SourceIP, &e.RequestID,
+			&before, &after, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.BeforeSizes, e.AfterSizes = []byte(before), []byte(after)
+
+		entry, err := mapAuditEntityToDomain(e)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,90 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type SQLiteOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteOutboxRepository(db *sql.DB) *SQLiteOutboxRepository {
+	return &SQLiteOutboxRepository{db: db}
+}
+
+func (r *SQLiteOutboxRepository) AppendOutboxEvent(ctx context.Context, event domain.OutboxEvent) error {
+	_, err := sqliteConn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO outbox (event_type, payload, created_at, next_attempt_at) VALUES (?, ?, ?, ?)`,
+		event.Type, string(event.Payload), sqliteTime(event.CreatedAt), sqliteTime(event.NextAttemptAt),
+	)
+	return err
+}
+
+func (r *SQLiteOutboxRepository) ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error) {
+	events := make([]domain.OutboxEvent, 0)
+	err := sqliteInTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`SELECT id, event_type, payload, created_at, attempts, next_attempt_at, last_error
+			FROM outbox WHERE published_at IS NULL AND next_attempt_at <= ?
+			ORDER BY id LIMIT ?`,
+			sqliteTime(now), limit,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var e domain.OutboxEvent
+			var payload string
+			if err := rows.Scan(&e.ID, &e.Type, &payload, &e.CreatedAt, &e.Attempts, &e.NextAttemptAt, &e.LastError); err != nil {
+				return err
+			}
+			e.Payload = []byte(payload)
+			events = append(events, e)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		args := []any{sqliteTime(leaseUntil)}
+		for _, e := range events {
+			args = append(args, e.ID)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(events)), ", ")
+		_, err = tx.ExecContext(ctx,
+			`UPDATE outbox SET next_attempt_at = ? WHERE id IN (`+placeholders+`)`, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		events[i].NextAttemptAt = leaseUntil
+	}
+	return events, nil
+}
+
+func (r *SQLiteOutboxRepository) MarkOutboxEventPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	_, err := sqliteConn(ctx, r.db).ExecContext(ctx,
+		`UPDATE outbox SET published_at = ?, attempts = attempts + 1, last_error = '' WHERE id = ?`,
+		sqliteTime(publishedAt), id,
+	)
+	return err
+}
+
+func (r *SQLiteOutboxRepository) MarkOutboxEventFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	_, err := sqliteConn(ctx, r.db).ExecContext(ctx,
+		`UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`,
+		lastError, sqliteTime(nextAttemptAt), id,
+	)
+	return err
+}
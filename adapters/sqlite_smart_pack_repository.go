@@ -0,0 +1,155 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type SQLiteSmartPackRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteSmartPackRepository(db *sql.DB) *SQLiteSmartPackRepository {
+	return &SQLiteSmartPackRepository{db: db}
+}
+
+func (r *SQLiteSmartPackRepository) GetPackSizes(ctx context.Context) ([]domain.SmartPack, error) {
+	rows, err := sqliteConn(ctx, r.db).QueryContext(ctx,
+		"SELECT size, label, sku, gtin, enabled FROM smartpack WHERE deleted_at IS NULL ORDER BY size DESC")
+	if err != nil {
+		return nil, err
+	}
+	return scanSQLiteSmartPacks(rows)
+}
+
+// GetVersionedPackSizes reads the active set in a single statement. Its
+// version is the highest row id, which every SetPackSizes raises.
+func (r *SQLiteSmartPackRepository) GetVersionedPackSizes(ctx context.Context) (domain.VersionedPackSizes, error) {
+	rows, err := sqliteConn(ctx, r.db).QueryContext(ctx,
+		"SELECT id, size, label, sku, gtin, enabled FROM smartpack WHERE deleted_at IS NULL ORDER BY size DESC")
+	if err != nil {
+		return domain.VersionedPackSizes{}, err
+	}
+	defer rows.Close()
+
+	var versioned domain.VersionedPackSizes
+	for rows.Next() {
+		var e SmartPackEntity
+		if err := rows.Scan(&e.ID, &e.Size, &e.Label, &e.SKU, &e.GTIN, &e.Enabled); err != nil {
+			return domain.VersionedPackSizes{}, err
+		}
+		versioned.Version = max(versioned.Version, int64(e.ID))
+		versioned.Packs = append(versioned.Packs, mapSmartPackEntityToDomain(e))
+	}
+	if err := rows.Err(); err != nil {
+		return domain.VersionedPackSizes{}, err
+	}
+	return versioned, nil
+}
+
+func (r *SQLiteSmartPackRepository) SetPackSizes(ctx context.Context, sizes []domain.SmartPack) ([]domain.SmartPack, error) {
+	var previous []domain.SmartPack
+	err := sqliteInTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		now := sqliteTime(time.Now())
+
+		// Mark all existing packs as deleted
+		rows, err := tx.QueryContext(ctx,
+			`UPDATE smartpack SET deleted_at = ? WHERE deleted_at IS NULL
+			RETURNING size, label, sku, gtin, enabled`, now)
+		if err != nil {
+			return err
+		}
+		previous, err = scanSQLiteSmartPacks(rows)
+		if err != nil {
+			return err
+		}
+
+		// Insert new pack sizes
+		for _, size := range sizes {
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO smartpack (size, label, sku, gtin, enabled, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+				size.Size, size.Label, size.SKU, size.GTIN, size.Enabled, now)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(previous, func(i, j int) bool { return previous[i].Size > previous[j].Size })
+	return previous, nil
+}
+
+func (r *SQLiteSmartPackRepository) ListRetiredGenerations(ctx context.Context) ([]domain.PackSizeGeneration, error) {
+	rows, err := sqliteConn(ctx, r.db).QueryContext(ctx,
+		`SELECT deleted_at, COUNT(*) FROM smartpack WHERE deleted_at IS NOT NULL
+		GROUP BY deleted_at ORDER BY deleted_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var generations []domain.PackSizeGeneration
+	for rows.Next() {
+		var g domain.PackSizeGeneration
+		if err := rows.Scan(&g.RetiredAt, &g.Packs); err != nil {
+			return nil, err
+		}
+		generations = append(generations, g)
+	}
+	return generations, rows.Err()
+}
+
+func (r *SQLiteSmartPackRepository) PurgeGenerations(ctx context.Context, retiredAt []time.Time, archive bool) (int, error) {
+	var purged int
+	err := sqliteInTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		for _, t := range retiredAt {
+			deletedAt := sqliteTime(t)
+			if archive {
+				_, err := tx.ExecContext(ctx,
+					`INSERT INTO smartpack_archive (id, size, label, sku, gtin, enabled, created_at, deleted_at, archived_at)
+					SELECT id, size, label, sku, gtin, enabled, created_at, deleted_at, ?
+					FROM smartpack WHERE deleted_at = ?`, sqliteTime(time.Now()), deletedAt)
+				if err != nil {
+					return err
+				}
+			}
+
+			res, err := tx.ExecContext(ctx, "DELETE FROM smartpack WHERE deleted_at = ?", deletedAt)
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			purged += int(n)
+		}
+		return nil
+	})
+	return purged, err
+}
+
+func scanSQLiteSmartPacks(rows *sql.Rows) ([]domain.SmartPack, error) {
+	defer rows.Close()
+
+	var sizes []domain.SmartPack
+	for rows.Next() {
+		var e SmartPackEntity
+		if err := rows.Scan(&e.Size, &e.Label, &e.SKU, &e.GTIN, &e.Enabled); err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, mapSmartPackEntityToDomain(e))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
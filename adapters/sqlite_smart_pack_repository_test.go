@@ -0,0 +1,124 @@
+package adapters_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/tests/contract"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteTestDB(t *testing.T) string {
+	t.Helper()
+
+	databaseURL := adapters.SQLiteScheme + filepath.Join(t.TempDir(), "smartpack.db")
+	// Empty path: the migrations embedded in the binary.
+	manager := adapters.NewMigrationManager(databaseURL, "")
+	_, err := manager.LoadMigrations()
+	require.NoError(t, err)
+	return databaseURL
+}
+
+func TestSQLiteSmartPackRepository(t *testing.T) {
+	contract.RunPackSizesRepository(t, func(t *testing.T) contract.PackSizesRepository {
+		db, err := adapters.OpenSQLite(context.Background(), newSQLiteTestDB(t))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		return adapters.NewSQLiteSmartPackRepository(db)
+	})
+}
+
+func TestSQLiteSmartPackRepositoryRetention(t *testing.T) {
+	contract.RunRetentionRepository(t, func(t *testing.T) contract.RetentionRepository {
+		db, err := adapters.OpenSQLite(context.Background(), newSQLiteTestDB(t))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		return adapters.NewSQLiteSmartPackRepository(db)
+	})
+}
+
+func TestSQLiteMigrationsSeedDefaultPackSizes(t *testing.T) {
+	ctx := context.Background()
+	db, err := adapters.OpenSQLite(ctx, newSQLiteTestDB(t))
+	require.NoError(t, err)
+	defer db.Close()
+
+	sizes, err := adapters.NewSQLiteSmartPackRepository(db).GetPackSizes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []domain.SmartPack{
+		{Size: 5000, Enabled: true},
+		{Size: 2000, Enabled: true},
+		{Size: 1000, Enabled: true},
+		{Size: 500, Enabled: true},
+		{Size: 250, Enabled: true},
+	}, sizes)
+}
+
+func TestSQLiteAuditRepository(t *testing.T) {
+	ctx := context.Background()
+	db, err := adapters.OpenSQLite(ctx, newSQLiteTestDB(t))
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := adapters.NewSQLiteAuditRepository(db)
+	start := time.Now().Add(-time.Second)
+	for _, actor := range []string{"alice", "bob", "alice"} {
+		require.NoError(t, repo.AppendAuditEntry(ctx, domain.AuditEntry{
+			Action:       domain.AuditActionSetPackSizes,
+			Actor:        actor,
+			ClaimedActor: "mallory",
+			Before:       []domain.SmartPack{{Size: 250, Enabled: true}},
+			After:        []domain.SmartPack{{Size: 500, Label: "Box", Enabled: false}},
+		}))
+	}
+
+	entries, err := repo.ListAuditEntries(ctx, domain.AuditFilter{Actor: "alice", From: &start, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Greater(t, entries[0].ID, entries[1].ID)
+	require.Equal(t, "mallory", entries[0].ClaimedActor)
+	require.Equal(t, []domain.SmartPack{{Size: 500, Label: "Box", Enabled: false}}, entries[0].After)
+	require.WithinDuration(t, time.Now(), entries[0].CreatedAt, time.Minute)
+
+	future := time.Now().Add(time.Hour)
+	entries, err = repo.ListAuditEntries(ctx, domain.AuditFilter{From: &future, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	_, err = db.ExecContext(ctx, "DELETE FROM audit_log")
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestSQLiteSmartPackRepositoryConcurrentWriterConflicts(t *testing.T) {
+	ctx := context.Background()
+	databaseURL := newSQLiteTestDB(t)
+
+	open := func() *sql.DB {
+		db, err := adapters.OpenSQLite(ctx, databaseURL)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	}
+	first, second := open(), open()
+	repo := adapters.NewSQLiteSmartPackRepository(first)
+
+	err := adapters.NewSQLiteTransactor(first).WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := repo.SetPackSizes(ctx, []domain.SmartPack{{Size: 250, Enabled: true}}); err != nil {
+			return err
+		}
+
+		// The first handle holds the write lock until it commits.
+		_, err := adapters.NewSQLiteSmartPackRepository(second).
+			SetPackSizes(context.Background(), []domain.SmartPack{{Size: 500, Enabled: true}})
+		require.ErrorIs(t, err, domain.ErrConflict)
+		return nil
+	})
+	require.NoError(t, err)
+}
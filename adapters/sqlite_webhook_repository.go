@@ -0,0 +1,202 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type SQLiteWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteWebhookRepository(db *sql.DB) *SQLiteWebhookRepository {
+	return &SQLiteWebhookRepository{db: db}
+}
+
+func (r *SQLiteWebhookRepository) CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	eventTypes, err := json.Marshal(sub.EventTypes)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	sub.CreatedAt = time.Now().UTC()
+	res, err := sqliteConn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO webhook_subscription (url, event_types, secret, created_at) VALUES (?, ?, ?, ?)`,
+		sub.URL, string(eventTypes), sub.Secret, sqliteTime(sub.CreatedAt),
+	)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	sub.ID, err = res.LastInsertId()
+	return sub, err
+}
+
+func (r *SQLiteWebhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	rows, err := sqliteConn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, url, event_types, secret, created_at FROM webhook_subscription ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make([]domain.WebhookSubscription, 0)
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		var eventTypes string
+		if err := rows.Scan(&sub.ID, &sub.URL, &eventTypes, &sub.Secret, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(eventTypes), &sub.EventTypes); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (r *SQLiteWebhookRepository) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	res, err := sqliteConn(ctx, r.db).ExecContext(ctx, `DELETE FROM webhook_subscription WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteWebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	return sqliteInTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		for _, d := range deliveries {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+				d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), d.Status,
+				sqliteTime(d.NextAttemptAt), sqliteTime(d.CreatedAt),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteWebhookRepository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := sqliteInTransaction(ctx, r.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx,
+			`SELECT `+webhookDeliveryColumns+` FROM webhook_delivery
+			WHERE status = 'pending' AND next_attempt_at <= ?
+			ORDER BY id LIMIT ?`,
+			sqliteTime(now), limit,
+		)
+		if err != nil {
+			return err
+		}
+		deliveries, err = scanSQLiteWebhookDeliveries(rows)
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		args := []any{sqliteTime(leaseUntil)}
+		for _, d := range deliveries {
+			args = append(args, d.ID)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(deliveries)), ", ")
+		_, err = tx.ExecContext(ctx,
+			`UPDATE webhook_delivery SET next_attempt_at = ? WHERE id IN (`+placeholders+`)`, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range deliveries {
+		deliveries[i].NextAttemptAt = leaseUntil
+	}
+	return deliveries, nil
+}
+
+func (r *SQLiteWebhookRepository) GetWebhookDelivery(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	rows, err := sqliteConn(ctx, r.db).QueryContext(ctx,
+		`SELECT `+webhookDeliveryColumns+` FROM webhook_delivery WHERE id = ?`, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	deliveries, err := scanSQLiteWebhookDeliveries(rows)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if len(deliveries) == 0 {
+		return domain.WebhookDelivery{}, domain.ErrWebhookDeliveryNotFound
+	}
+	return deliveries[0], nil
+}
+
+func (r *SQLiteWebhookRepository) UpdateWebhookDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	var deliveredAt any
+	if d.DeliveredAt != nil {
+		deliveredAt = sqliteTime(*d.DeliveredAt)
+	}
+	_, err := sqliteConn(ctx, r.db).ExecContext(ctx,
+		`UPDATE webhook_delivery SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?,
+			response_status = ?, delivered_at = ?
+		WHERE id = ?`,
+		d.Status, d.Attempts, sqliteTime(d.NextAttemptAt), d.LastError, d.ResponseStatus, deliveredAt, d.ID,
+	)
+	return err
+}
+
+func (r *SQLiteWebhookRepository) ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	var exists bool
+	err := sqliteConn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM webhook_subscription WHERE id = ?)`, filter.SubscriptionID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrWebhookNotFound
+	}
+
+	rows, err := sqliteConn(ctx, r.db).QueryContext(ctx,
+		`SELECT `+webhookDeliveryColumns+` FROM webhook_delivery
+		WHERE subscription_id = ? AND (? = '' OR status = ?)
+		ORDER BY id DESC LIMIT ? OFFSET ?`,
+		filter.SubscriptionID, filter.Status, filter.Status, filter.Limit, filter.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanSQLiteWebhookDeliveries(rows)
+}
+
+func scanSQLiteWebhookDeliveries(rows *sql.Rows) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var payload string
+		var deliveredAt sql.NullTime
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastError, &d.ResponseStatus, &d.CreatedAt, &deliveredAt); err != nil {
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
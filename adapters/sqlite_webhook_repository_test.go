@@ -0,0 +1,20 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/tests/contract"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteWebhookRepository(t *testing.T) {
+	contract.RunWebhookRepository(t, func(t *testing.T) contract.WebhookRepository {
+		db, err := adapters.OpenSQLite(context.Background(), newSQLiteTestDB(t))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		return adapters.NewSQLiteWebhookRepository(db)
+	})
+}
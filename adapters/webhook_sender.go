@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// Headers added to webhook subscription deliveries on top of EventIDHeader
+// and EventTypeHeader. SignatureHeader carries domain.SignWebhookPayload.
+const (
+	SignatureHeader  = "X-Smartpack-Signature"
+	DeliveryIDHeader = "X-Delivery-Id"
+)
+
+// HTTPWebhookSender delivers webhook subscription events signed with the
+// subscription secret. Any status outside 2xx counts as a failed attempt.
+type HTTPWebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewHTTPWebhookSender(timeout time.Duration) *HTTPWebhookSender {
+	return &HTTPWebhookSender{client: &http.Client{Timeout: timeout}, now: time.Now}
+}
+
+func (s *HTTPWebhookSender) Send(ctx context.Context, url, secret string, delivery domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(DeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, domain.SignWebhookPayload(secret, s.now(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
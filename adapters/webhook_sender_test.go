@@ -0,0 +1,65 @@
+package adapters_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPWebhookSender(t *testing.T) {
+	const secret = "whsec_0123456789abcdef"
+	delivery := domain.WebhookDelivery{
+		ID:        9,
+		EventID:   "evt_1",
+		EventType: domain.EventCalculationCompleted,
+		Payload:   []byte(`{"id":"evt_1","type":"calculation.completed"}`),
+	}
+
+	t.Run("posts the signed payload", func(t *testing.T) {
+		var got *http.Request
+		var body []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		status, err := adapters.NewHTTPWebhookSender(time.Second).Send(context.Background(), srv.URL, secret, delivery)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, status)
+
+		require.Equal(t, "evt_1", got.Header.Get(adapters.EventIDHeader))
+		require.Equal(t, domain.EventCalculationCompleted, got.Header.Get(adapters.EventTypeHeader))
+		require.Equal(t, "9", got.Header.Get(adapters.DeliveryIDHeader))
+		require.Equal(t, string(delivery.Payload), string(body))
+
+		// The receiver recomputes the signature from the header timestamp.
+		signature := got.Header.Get(adapters.SignatureHeader)
+		m := regexp.MustCompile(`^t=(\d+),v1=[0-9a-f]{64}$`).FindStringSubmatch(signature)
+		require.NotNil(t, m, signature)
+		ts, err := strconv.ParseInt(m[1], 10, 64)
+		require.NoError(t, err)
+		require.Equal(t, domain.SignWebhookPayload(secret, time.Unix(ts, 0), body), signature)
+	})
+
+	t.Run("returns the status of a non-2xx response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		status, err := adapters.NewHTTPWebhookSender(time.Second).Send(context.Background(), srv.URL, secret, delivery)
+		require.ErrorContains(t, err, "502")
+		require.Equal(t, http.StatusBadGateway, status)
+	})
+}
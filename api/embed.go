@@ -0,0 +1,7 @@
+// Package api embeds the OpenAPI specification served by the API.
+package api
+
+import _ "embed"
+
+//go:embed api.yml
+var Spec []byte
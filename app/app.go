@@ -1,7 +1,8 @@
 package app
 
 import (
-	smartCalculator "github.com/rossi1/smart-pack/adapters/smart_calculator"
+	"context"
+
 	"github.com/rossi1/smart-pack/app/command"
 	"github.com/rossi1/smart-pack/app/query"
 	appConfig "github.com/rossi1/smart-pack/config"
@@ -11,15 +12,52 @@ import (
 type Application struct {
 	ErrorReporter  domain.ErrorReporter
 	AppConfig      *appConfig.AppConfig
-	PackCalculator smartCalculator.PackCalculator
-	Commands       *Commands
-	Queries        *Queries
+	DatabaseHealth DatabaseHealth
+	// SchemaHealth is nil when the storage has no migrations.
+	SchemaHealth SchemaHealth
+	// PackSizesChanges signals pack-size changes to streaming clients.
+	PackSizesChanges PackSizesChanges
+	Commands         *Commands
+	Queries          *Queries
+}
+
+// DatabaseHealth reports the state of the database connection pool.
+type DatabaseHealth interface {
+	Ping(ctx context.Context) error
+	Stats() domain.DatabaseStats
+}
+
+// SchemaHealth reports whether the database schema is clean and up to date.
+type SchemaHealth interface {
+	CheckSchema(ctx context.Context) error
+}
+
+// PackSizesChanges signals that the pack sizes may have changed, whether
+// committed by this process, another replica or a file reload.
+type PackSizesChanges interface {
+	Subscribe() (<-chan struct{}, func())
 }
 
 type Commands struct {
-	SetPackSizes command.SetPackSizesHandler
+	SetPackSizes   command.SetPackSizesHandler
+	PurgePackSizes command.PurgePackSizesHandler
+	// RelayOutbox is nil when no outbox publisher is configured and
+	// webhooks are disabled.
+	RelayOutbox           command.RelayOutboxHandler
+	RegisterWebhook       command.RegisterWebhookHandler
+	DeleteWebhook         command.DeleteWebhookHandler
+	ReplayWebhookDelivery command.ReplayWebhookDeliveryHandler
+	// EnqueueWebhookEvent and DeliverWebhooks are nil when webhooks are
+	// disabled.
+	EnqueueWebhookEvent command.EnqueueWebhookEventHandler
+	DeliverWebhooks     command.DeliverWebhooksHandler
 }
 
 type Queries struct {
-	GetPackSizes query.GetPackSizesHandler
+	GetPackSizes          query.GetPackSizesHandler
+	GetVersionedPackSizes query.GetVersionedPackSizesHandler
+	CalculatePacks        query.CalculatePacksHandler
+	ListAuditEntries      query.ListAuditEntriesHandler
+	ListWebhooks          query.ListWebhooksHandler
+	ListWebhookDeliveries query.ListWebhookDeliveriesHandler
 }
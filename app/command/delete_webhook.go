@@ -0,0 +1,35 @@
+package command
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type DeleteWebhookCommand struct {
+	ID int64
+}
+
+//go:generate mockgen -package=command -destination=delete_webhook.mock.go -source=delete_webhook.go
+type DeleteWebhookRepository interface {
+	// DeleteWebhookSubscription removes the subscription and its delivery
+	// log, or returns domain.ErrWebhookNotFound.
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+}
+
+type DeleteWebhookHandler decorator.CommandHandler[*DeleteWebhookCommand]
+
+type deleteWebhookHandler struct {
+	repo DeleteWebhookRepository
+}
+
+func NewDeleteWebhookHandler(repo DeleteWebhookRepository) DeleteWebhookHandler {
+	return decorator.ApplyAuthorizedCommandDecorators[*DeleteWebhookCommand](&deleteWebhookHandler{
+		repo: repo,
+	}, domain.PermissionManageWebhooks)
+}
+
+func (h *deleteWebhookHandler) Handle(ctx context.Context, cmd *DeleteWebhookCommand) error {
+	return h.repo.DeleteWebhookSubscription(ctx, cmd.ID)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: delete_webhook.go
+
+// Package command is a generated GoMock package.
+package command
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeleteWebhookRepository is a mock of DeleteWebhookRepository interface.
+type MockDeleteWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeleteWebhookRepositoryMockRecorder
+}
+
+// MockDeleteWebhookRepositoryMockRecorder is the mock recorder for MockDeleteWebhookRepository.
+type MockDeleteWebhookRepositoryMockRecorder struct {
+	mock *MockDeleteWebhookRepository
+}
+
+// NewMockDeleteWebhookRepository creates a new mock instance.
+func NewMockDeleteWebhookRepository(ctrl *gomock.Controller) *MockDeleteWebhookRepository {
+	mock := &MockDeleteWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockDeleteWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeleteWebhookRepository) EXPECT() *MockDeleteWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockDeleteWebhookRepository) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockDeleteWebhookRepositoryMockRecorder) DeleteWebhookSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockDeleteWebhookRepository)(nil).DeleteWebhookSubscription), ctx, id)
+}
@@ -0,0 +1,97 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// webhookLease is how long a claimed delivery stays invisible to other
+// workers; it must outlast the webhook timeout.
+const webhookLease = time.Minute
+
+type DeliverWebhooksCommand struct {
+	// Limit caps how many deliveries one run claims.
+	Limit int
+	// MaxAttempts is how many times a delivery is tried before it fails.
+	MaxAttempts int
+
+	// Succeeded and Failed are filled in by the handler. Failed counts
+	// failed attempts, including those that will be retried.
+	Succeeded int
+	Failed    int
+}
+
+//go:generate mockgen -package=command -destination=deliver_webhooks.mock.go -source=deliver_webhooks.go
+type DeliverWebhooksRepository interface {
+	ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// ClaimWebhookDeliveries returns up to limit pending deliveries that are
+	// due at now, oldest first, and hides them from other claims until
+	// leaseUntil.
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+}
+
+type WebhookSender interface {
+	// Send posts the delivery payload signed with secret and returns the
+	// response status, or 0 when no response was received.
+	Send(ctx context.Context, url, secret string, delivery domain.WebhookDelivery) (int, error)
+}
+
+type DeliverWebhooksHandler decorator.CommandHandler[*DeliverWebhooksCommand]
+
+type deliverWebhooksHandler struct {
+	repo   DeliverWebhooksRepository
+	sender WebhookSender
+	now    func() time.Time
+}
+
+func NewDeliverWebhooksHandler(repo DeliverWebhooksRepository, sender WebhookSender) DeliverWebhooksHandler {
+	return decorator.ApplyCommandDecorators[*DeliverWebhooksCommand](&deliverWebhooksHandler{
+		repo:   repo,
+		sender: sender,
+		now:    time.Now,
+	})
+}
+
+func (h *deliverWebhooksHandler) Handle(ctx context.Context, cmd *DeliverWebhooksCommand) error {
+	cmd.Succeeded, cmd.Failed = 0, 0
+
+	now := h.now().UTC()
+	deliveries, err := h.repo.ClaimWebhookDeliveries(ctx, now, now.Add(webhookLease), cmd.Limit)
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+
+	subs, err := h.repo.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]domain.WebhookSubscription, len(subs))
+	for _, sub := range subs {
+		byID[sub.ID] = sub
+	}
+
+	for _, d := range deliveries {
+		sub, ok := byID[d.SubscriptionID]
+		if !ok {
+			// Deleted after the claim; its deliveries went with it.
+			continue
+		}
+
+		status, err := h.sender.Send(ctx, sub.URL, sub.Secret, d)
+		if err != nil {
+			cmd.Failed++
+			d.Retry(h.now().UTC(), err.Error(), status, cmd.MaxAttempts)
+		} else {
+			cmd.Succeeded++
+			d.Succeed(h.now().UTC(), status)
+		}
+		if err := h.repo.UpdateWebhookDelivery(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockDeliverWebhooksRepository(ctrl)
+	sender := NewMockWebhookSender(ctrl)
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	sub := domain.WebhookSubscription{ID: 7, URL: "https://partner.example.com/hooks", Secret: "whsec_0123456789abcdef"}
+	delivered := domain.WebhookDelivery{ID: 1, SubscriptionID: 7, Status: domain.WebhookDeliveryPending}
+	retried := domain.WebhookDelivery{ID: 2, SubscriptionID: 7, Status: domain.WebhookDeliveryPending, Attempts: 1}
+	exhausted := domain.WebhookDelivery{ID: 3, SubscriptionID: 7, Status: domain.WebhookDeliveryPending, Attempts: 2}
+	orphaned := domain.WebhookDelivery{ID: 4, SubscriptionID: 8, Status: domain.WebhookDeliveryPending}
+
+	repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), now, now.Add(webhookLease), 10).
+		Return([]domain.WebhookDelivery{delivered, retried, exhausted, orphaned}, nil)
+	repo.EXPECT().ListWebhookSubscriptions(gomock.Any()).Return([]domain.WebhookSubscription{sub}, nil)
+	sender.EXPECT().Send(gomock.Any(), sub.URL, sub.Secret, delivered).Return(204, nil)
+	sender.EXPECT().Send(gomock.Any(), sub.URL, sub.Secret, retried).Return(503, errors.New("webhook responded with 503"))
+	sender.EXPECT().Send(gomock.Any(), sub.URL, sub.Secret, exhausted).Return(0, errors.New("connection refused"))
+
+	repo.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, d domain.WebhookDelivery) error {
+			switch d.ID {
+			case 1:
+				require.Equal(t, domain.WebhookDeliverySucceeded, d.Status)
+				require.Equal(t, 204, d.ResponseStatus)
+				require.Equal(t, now, *d.DeliveredAt)
+			case 2:
+				// Second attempt failed: retried after 2s.
+				require.Equal(t, domain.WebhookDeliveryPending, d.Status)
+				require.Equal(t, 2, d.Attempts)
+				require.Equal(t, now.Add(2*time.Second), d.NextAttemptAt)
+				require.Equal(t, 503, d.ResponseStatus)
+			case 3:
+				require.Equal(t, domain.WebhookDeliveryFailed, d.Status)
+				require.Equal(t, "connection refused", d.LastError)
+			default:
+				t.Fatalf("unexpected update of delivery %d", d.ID)
+			}
+			return nil
+		}).Times(3)
+
+	h := &deliverWebhooksHandler{repo: repo, sender: sender, now: func() time.Time { return now }}
+	cmd := &DeliverWebhooksCommand{Limit: 10, MaxAttempts: 3}
+	require.NoError(t, h.Handle(context.Background(), cmd))
+	require.Equal(t, 1, cmd.Succeeded)
+	require.Equal(t, 2, cmd.Failed)
+}
+
+func TestDeliverWebhooksNothingDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockDeliverWebhooksRepository(ctrl)
+	sender := NewMockWebhookSender(ctrl)
+
+	repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any(), gomock.Any(), 10).Return(nil, nil)
+
+	h := NewDeliverWebhooksHandler(repo, sender)
+	require.NoError(t, h.Handle(context.Background(), &DeliverWebhooksCommand{Limit: 10, MaxAttempts: 3}))
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type EnqueueWebhookEventCommand struct {
+	// EventID is generated when empty. Enqueueing the same id again does not
+	// queue a second delivery.
+	EventID    string
+	Type       string
+	OccurredAt time.Time
+	// Data is marshalled to JSON as the data of the webhook event.
+	Data any
+
+	// Deliveries is the number of subscriptions the event was queued for.
+	Deliveries int
+}
+
+//go:generate mockgen -package=command -destination=enqueue_webhook_event.mock.go -source=enqueue_webhook_event.go
+type EnqueueWebhookEventRepository interface {
+	ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// EnqueueWebhookDeliveries skips deliveries already queued for the same
+	// subscription and event id.
+	EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+}
+
+type EnqueueWebhookEventHandler decorator.CommandHandler[*EnqueueWebhookEventCommand]
+
+type enqueueWebhookEventHandler struct {
+	repo EnqueueWebhookEventRepository
+}
+
+func NewEnqueueWebhookEventHandler(repo EnqueueWebhookEventRepository) EnqueueWebhookEventHandler {
+	return decorator.ApplyCommandDecorators[*EnqueueWebhookEventCommand](&enqueueWebhookEventHandler{
+		repo: repo,
+	})
+}
+
+// Handle queues one delivery per subscription to the event type. The
+// deliveries are sent later by DeliverWebhooks.
+func (h *enqueueWebhookEventHandler) Handle(ctx context.Context, cmd *EnqueueWebhookEventCommand) error {
+	cmd.Deliveries = 0
+
+	subs, err := h.repo.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	var subscribed []domain.WebhookSubscription
+	for _, sub := range subs {
+		if sub.Subscribes(cmd.Type) {
+			subscribed = append(subscribed, sub)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	eventID := cmd.EventID
+	if eventID == "" {
+		if eventID, err = domain.NewWebhookEventID(); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(cmd.Data)
+	if err != nil {
+		return err
+	}
+	occurredAt := cmd.OccurredAt.UTC()
+	payload, err := json.Marshal(domain.WebhookEvent{
+		ID:         eventID,
+		Type:       cmd.Type,
+		OccurredAt: occurredAt,
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(subscribed))
+	for _, sub := range subscribed {
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      cmd.Type,
+			Payload:        payload,
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  occurredAt,
+			CreatedAt:      occurredAt,
+		})
+	}
+	if err := h.repo.EnqueueWebhookDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	cmd.Deliveries = len(deliveries)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: enqueue_webhook_event.go
+
+// Package command is a generated GoMock package.
+package command
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/rossi1/smart-pack/domain"
+)
+
+// MockEnqueueWebhookEventRepository is a mock of EnqueueWebhookEventRepository interface.
+type MockEnqueueWebhookEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEnqueueWebhookEventRepositoryMockRecorder
+}
+
+// MockEnqueueWebhookEventRepositoryMockRecorder is the mock recorder for MockEnqueueWebhookEventRepository.
+type MockEnqueueWebhookEventRepositoryMockRecorder struct {
+	mock *MockEnqueueWebhookEventRepository
+}
+
+// NewMockEnqueueWebhookEventRepository creates a new mock instance.
+func NewMockEnqueueWebhookEventRepository(ctrl *gomock.Controller) *MockEnqueueWebhookEventRepository {
+	mock := &MockEnqueueWebhookEventRepository{ctrl: ctrl}
+	mock.recorder = &MockEnqueueWebhookEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEnqueueWebhookEventRepository) EXPECT() *MockEnqueueWebhookEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockEnqueueWebhookEventRepository) EnqueueWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockEnqueueWebhookEventRepositoryMockRecorder) EnqueueWebhookDeliveries(ctx, deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockEnqueueWebhookEventRepository)(nil).EnqueueWebhookDeliveries), ctx, deliveries)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockEnqueueWebhookEventRepository) ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", ctx)
+	ret0, _ := ret[0].([]domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockEnqueueWebhookEventRepositoryMockRecorder) ListWebhookSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockEnqueueWebhookEventRepository)(nil).ListWebhookSubscriptions), ctx)
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type PurgePackSizesCommand struct {
+	Policy domain.RetentionPolicy
+	// DryRun reports what the policy would remove without removing it.
+	DryRun bool
+
+	// Report is filled in by the handler.
+	Report domain.PurgeReport
+}
+
+//go:generate mockgen -package=command -destination=purge_pack_sizes.mock.go -source=purge_pack_sizes.go
+type PurgePackSizesRepository interface {
+	// ListRetiredGenerations returns the soft-deleted generations, newest first.
+	ListRetiredGenerations(ctx context.Context) ([]domain.PackSizeGeneration, error)
+	// PurgeGenerations removes the packs retired at the given times, moving
+	// them to the archive when archive is set, and returns how many it removed.
+	PurgeGenerations(ctx context.Context, retiredAt []time.Time, archive bool) (int, error)
+}
+
+type PurgePackSizesHandler decorator.CommandHandler[*PurgePackSizesCommand]
+
+type purgePackSizesHandler struct {
+	repo PurgePackSizesRepository
+	tx   Transactor
+	now  func() time.Time
+}
+
+func NewPurgePackSizesHandler(repo PurgePackSizesRepository, tx Transactor) PurgePackSizesHandler {
+	return decorator.ApplyAuthorizedCommandDecorators[*PurgePackSizesCommand](&purgePackSizesHandler{
+		repo: repo,
+		tx:   tx,
+		now:  time.Now,
+	}, domain.PermissionWritePackSizes)
+}
+
+func (h *purgePackSizesHandler) Handle(ctx context.Context, cmd *PurgePackSizesCommand) error {
+	cmd.Report = domain.PurgeReport{Archived: cmd.Policy.Archive, DryRun: cmd.DryRun}
+	if !cmd.Policy.Enabled() {
+		return nil
+	}
+
+	return h.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		generations, err := h.repo.ListRetiredGenerations(ctx)
+		if err != nil {
+			return err
+		}
+
+		expired := cmd.Policy.Expired(generations, h.now())
+		cmd.Report = domain.NewPurgeReport(expired, cmd.Policy.Archive, cmd.DryRun)
+		if cmd.DryRun || len(expired) == 0 {
+			return nil
+		}
+
+		retiredAt := make([]time.Time, 0, len(expired))
+		for _, g := range expired {
+			retiredAt = append(retiredAt, g.RetiredAt)
+		}
+		purged, err := h.repo.PurgeGenerations(ctx, retiredAt, cmd.Policy.Archive)
+		if err != nil {
+			return err
+		}
+		cmd.Report.Packs = purged
+		return nil
+	})
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: purge_pack_sizes.go
+
+// Package command is a generated GoMock package.
+package command
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/rossi1/smart-pack/domain"
+)
+
+// MockPurgePackSizesRepository is a mock of PurgePackSizesRepository interface.
+type MockPurgePackSizesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPurgePackSizesRepositoryMockRecorder
+}
+
+// MockPurgePackSizesRepositoryMockRecorder is the mock recorder for MockPurgePackSizesRepository.
+type MockPurgePackSizesRepositoryMockRecorder struct {
+	mock *MockPurgePackSizesRepository
+}
+
+// NewMockPurgePackSizesRepository creates a new mock instance.
+func NewMockPurgePackSizesRepository(ctrl *gomock.Controller) *MockPurgePackSizesRepository {
+	mock := &MockPurgePackSizesRepository{ctrl: ctrl}
+	mock.recorder = &MockPurgePackSizesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPurgePackSizesRepository) EXPECT() *MockPurgePackSizesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListRetiredGenerations mocks base method.
+func (m *MockPurgePackSizesRepository) ListRetiredGenerations(ctx context.Context) ([]domain.PackSizeGeneration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRetiredGenerations", ctx)
+	ret0, _ := ret[0].([]domain.PackSizeGeneration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRetiredGenerations indicates an expected call of ListRetiredGenerations.
+func (mr *MockPurgePackSizesRepositoryMockRecorder) ListRetiredGenerations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRetiredGenerations", reflect.TypeOf((*MockPurgePackSizesRepository)(nil).ListRetiredGenerations), ctx)
+}
+
+// PurgeGenerations mocks base method.
+func (m *MockPurgePackSizesRepository) PurgeGenerations(ctx context.Context, retiredAt []time.Time, archive bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeGenerations", ctx, retiredAt, archive)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeGenerations indicates an expected call of PurgeGenerations.
+func (mr *MockPurgePackSizesRepositoryMockRecorder) PurgeGenerations(ctx, retiredAt, archive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeGenerations", reflect.TypeOf((*MockPurgePackSizesRepository)(nil).PurgeGenerations), ctx, retiredAt, archive)
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type RegisterWebhookCommand struct {
+	URL        string
+	EventTypes []string
+	// Secret is generated when empty.
+	Secret string
+
+	// Subscription is filled in by the handler, including the secret.
+	Subscription domain.WebhookSubscription
+}
+
+//go:generate mockgen -package=command -destination=register_webhook.mock.go -source=register_webhook.go
+type RegisterWebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error)
+}
+
+type RegisterWebhookHandler decorator.CommandHandler[*RegisterWebhookCommand]
+
+type registerWebhookHandler struct {
+	repo RegisterWebhookRepository
+}
+
+func NewRegisterWebhookHandler(repo RegisterWebhookRepository) RegisterWebhookHandler {
+	return decorator.ApplyAuthorizedCommandDecorators[*RegisterWebhookCommand](&registerWebhookHandler{
+		repo: repo,
+	}, domain.PermissionManageWebhooks)
+}
+
+func (h *registerWebhookHandler) Handle(ctx context.Context, cmd *RegisterWebhookCommand) error {
+	sub, err := domain.NewWebhookSubscription(cmd.URL, cmd.EventTypes, cmd.Secret)
+	if err != nil {
+		return err
+	}
+
+	cmd.Subscription, err = h.repo.CreateWebhookSubscription(ctx, sub)
+	return err
+}
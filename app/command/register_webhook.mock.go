@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: register_webhook.go
+
+// Package command is a generated GoMock package.
+package command
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/rossi1/smart-pack/domain"
+)
+
+// MockRegisterWebhookRepository is a mock of RegisterWebhookRepository interface.
+type MockRegisterWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegisterWebhookRepositoryMockRecorder
+}
+
+// MockRegisterWebhookRepositoryMockRecorder is the mock recorder for MockRegisterWebhookRepository.
+type MockRegisterWebhookRepositoryMockRecorder struct {
+	mock *MockRegisterWebhookRepository
+}
+
+// NewMockRegisterWebhookRepository creates a new mock instance.
+func NewMockRegisterWebhookRepository(ctrl *gomock.Controller) *MockRegisterWebhookRepository {
+	mock := &MockRegisterWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockRegisterWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegisterWebhookRepository) EXPECT() *MockRegisterWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockRegisterWebhookRepository) CreateWebhookSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", ctx, sub)
+	ret0, _ := ret[0].(domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockRegisterWebhookRepositoryMockRecorder) CreateWebhookSubscription(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockRegisterWebhookRepository)(nil).CreateWebhookSubscription), ctx, sub)
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// outboxLease is how long a claimed event stays invisible to other relays.
+// It must outlast a publish attempt, or another replica may deliver the
+// event again while the first is still sending it.
+const outboxLease = time.Minute
+
+type RelayOutboxCommand struct {
+	// Limit caps how many events one run claims.
+	Limit int
+
+	// Published and Failed are filled in by the handler.
+	Published int
+	Failed    int
+}
+
+//go:generate mockgen -package=command -destination=relay_outbox.mock.go -source=relay_outbox.go
+type RelayOutboxRepository interface {
+	// ClaimOutboxEvents returns up to limit undelivered events that are due at
+	// now, oldest first, and hides them from other claims until leaseUntil.
+	ClaimOutboxEvents(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64, publishedAt time.Time) error
+	// MarkOutboxEventFailed records a failed attempt and when to retry.
+	MarkOutboxEventFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.OutboxEvent) error
+}
+
+type RelayOutboxHandler decorator.CommandHandler[*RelayOutboxCommand]
+
+type relayOutboxHandler struct {
+	repo      RelayOutboxRepository
+	publisher EventPublisher
+	now       func() time.Time
+}
+
+func NewRelayOutboxHandler(repo RelayOutboxRepository, publisher EventPublisher) RelayOutboxHandler {
+	return decorator.ApplyCommandDecorators[*RelayOutboxCommand](&relayOutboxHandler{
+		repo:      repo,
+		publisher: publisher,
+		now:       time.Now,
+	})
+}
+
+// Handle publishes the due events one by one. An event is only marked as
+// published after the publisher accepted it, so a crash in between delivers
+// it again once the lease expires.
+func (h *relayOutboxHandler) Handle(ctx context.Context, cmd *RelayOutboxCommand) error {
+	cmd.Published, cmd.Failed = 0, 0
+
+	now := h.now().UTC()
+	events, err := h.repo.ClaimOutboxEvents(ctx, now, now.Add(outboxLease), cmd.Limit)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := h.publisher.Publish(ctx, event); err != nil {
+			cmd.Failed++
+			retryAt := h.now().UTC().Add(domain.OutboxRetryDelay(event.Attempts + 1))
+			if err := h.repo.MarkOutboxEventFailed(ctx, event.ID, err.Error(), retryAt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		cmd.Published++
+		if err := h.repo.MarkOutboxEventPublished(ctx, event.ID, h.now().UTC()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
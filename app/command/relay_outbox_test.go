@@ -0,0 +1,36 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayOutbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := NewMockRelayOutboxRepository(ctrl)
+	publisher := NewMockEventPublisher(ctrl)
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	delivered := domain.OutboxEvent{ID: 1}
+	failing := domain.OutboxEvent{ID: 2, Attempts: 2}
+
+	repo.EXPECT().ClaimOutboxEvents(gomock.Any(), now, now.Add(outboxLease), 10).
+		Return([]domain.OutboxEvent{delivered, failing}, nil)
+	publisher.EXPECT().Publish(gomock.Any(), delivered).Return(nil)
+	publisher.EXPECT().Publish(gomock.Any(), failing).Return(errors.New("connection refused"))
+	repo.EXPECT().MarkOutboxEventPublished(gomock.Any(), int64(1), now).Return(nil)
+	// Third attempt failed: retried after 4s.
+	repo.EXPECT().MarkOutboxEventFailed(gomock.Any(), int64(2), "connection refused", now.Add(4*time.Second)).Return(nil)
+
+	h := &relayOutboxHandler{repo: repo, publisher: publisher, now: func() time.Time { return now }}
+	cmd := &RelayOutboxCommand{Limit: 10}
+	require.NoError(t, h.Handle(context.Background(), cmd))
+	require.Equal(t, 1, cmd.Published)
+	require.Equal(t, 1, cmd.Failed)
+}
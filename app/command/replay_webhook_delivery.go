@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type ReplayWebhookDeliveryCommand struct {
+	ID int64
+}
+
+//go:generate mockgen -package=command -destination=replay_webhook_delivery.mock.go -source=replay_webhook_delivery.go
+type ReplayWebhookDeliveryRepository interface {
+	// GetWebhookDelivery returns domain.ErrWebhookDeliveryNotFound when id
+	// does not exist.
+	GetWebhookDelivery(ctx context.Context, id int64) (domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+}
+
+type ReplayWebhookDeliveryHandler decorator.CommandHandler[*ReplayWebhookDeliveryCommand]
+
+type replayWebhookDeliveryHandler struct {
+	repo ReplayWebhookDeliveryRepository
+	tx   Transactor
+}
+
+func NewReplayWebhookDeliveryHandler(repo ReplayWebhookDeliveryRepository, tx Transactor) ReplayWebhookDeliveryHandler {
+	return decorator.ApplyAuthorizedCommandDecorators[*ReplayWebhookDeliveryCommand](&replayWebhookDeliveryHandler{
+		repo: repo,
+		tx:   tx,
+	}, domain.PermissionManageWebhooks)
+}
+
+func (h *replayWebhookDeliveryHandler) Handle(ctx context.Context, cmd *ReplayWebhookDeliveryCommand) error {
+	return h.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		delivery, err := h.repo.GetWebhookDelivery(ctx, cmd.ID)
+		if err != nil {
+			return err
+		}
+		if err := delivery.Replay(time.Now().UTC()); err != nil {
+			return err
+		}
+		return h.repo.UpdateWebhookDelivery(ctx, delivery)
+	})
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: replay_webhook_delivery.go
+
+// Package command is a generated GoMock package.
+package command
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/rossi1/smart-pack/domain"
+)
+
+// MockReplayWebhookDeliveryRepository is a mock of ReplayWebhookDeliveryRepository interface.
+type MockReplayWebhookDeliveryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplayWebhookDeliveryRepositoryMockRecorder
+}
+
+// MockReplayWebhookDeliveryRepositoryMockRecorder is the mock recorder for MockReplayWebhookDeliveryRepository.
+type MockReplayWebhookDeliveryRepositoryMockRecorder struct {
+	mock *MockReplayWebhookDeliveryRepository
+}
+
+// NewMockReplayWebhookDeliveryRepository creates a new mock instance.
+func NewMockReplayWebhookDeliveryRepository(ctrl *gomock.Controller) *MockReplayWebhookDeliveryRepository {
+	mock := &MockReplayWebhookDeliveryRepository{ctrl: ctrl}
+	mock.recorder = &MockReplayWebhookDeliveryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplayWebhookDeliveryRepository) EXPECT() *MockReplayWebhookDeliveryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockReplayWebhookDeliveryRepository) GetWebhookDelivery(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, id)
+	ret0, _ := ret[0].(domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockReplayWebhookDeliveryRepositoryMockRecorder) GetWebhookDelivery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockReplayWebhookDeliveryRepository)(nil).GetWebhookDelivery), ctx, id)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockReplayWebhookDeliveryRepository) UpdateWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockReplayWebhookDeliveryRepositoryMockRecorder) UpdateWebhookDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockReplayWebhookDeliveryRepository)(nil).UpdateWebhookDelivery), ctx, delivery)
+}
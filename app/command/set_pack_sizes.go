@@ -2,32 +2,120 @@ package command
 
 import (
 	"context"
+	"time"
 
 	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/common/requestmeta"
 	"github.com/rossi1/smart-pack/domain"
 )
 
 type SetPackSizesCommand struct {
 	Sizes []domain.SmartPack
+	// DryRun validates the sizes without replacing the active set.
+	DryRun bool
 }
 
 //go:generate mockgen -package=command -destination=set_pack_sizes.mock.go -source=set_pack_sizes.go
 type SetPackSizesRepository interface {
-	SetPackSizes(ctx context.Context, sizes []domain.SmartPack) error
+	// SetPackSizes replaces the active set and returns the one it replaced.
+	SetPackSizes(ctx context.Context, sizes []domain.SmartPack) ([]domain.SmartPack, error)
+}
+
+type AuditRepository interface {
+	AppendAuditEntry(ctx context.Context, entry domain.AuditEntry) error
+}
+
+type OutboxRepository interface {
+	AppendOutboxEvent(ctx context.Context, event domain.OutboxEvent) error
 }
 
 type SetPackSizesHandler decorator.CommandHandler[*SetPackSizesCommand]
 
 type setPackSizesHandler struct {
-	repo SetPackSizesRepository
+	repo   SetPackSizesRepository
+	audit  AuditRepository
+	outbox OutboxRepository
+	tx     Transactor
+	limits domain.InputLimits
 }
 
-func NewSetPackSizesHandler(repo SetPackSizesRepository) SetPackSizesHandler {
-	return decorator.ApplyCommandDecorators[*SetPackSizesCommand](&setPackSizesHandler{
-		repo: repo,
-	})
+func NewSetPackSizesHandler(
+	repo SetPackSizesRepository,
+	audit AuditRepository,
+	outbox OutboxRepository,
+	tx Transactor,
+	limits domain.InputLimits,
+) SetPackSizesHandler {
+	return decorator.ApplyAuthorizedCommandDecorators[*SetPackSizesCommand](&setPackSizesHandler{
+		repo:   repo,
+		audit:  audit,
+		outbox: outbox,
+		tx:     tx,
+		limits: limits,
+	}, domain.PermissionWritePackSizes)
 }
 
 func (h *setPackSizesHandler) Handle(ctx context.Context, cmd *SetPackSizesCommand) error {
-	return h.repo.SetPackSizes(ctx, cmd.Sizes)
+	if err := domain.ValidatePackSizes(cmd.Sizes, h.limits); err != nil {
+		return err
+	}
+	if cmd.DryRun {
+		return nil
+	}
+
+	return h.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := h.repo.SetPackSizes(ctx, cmd.Sizes)
+		if err != nil {
+			return err
+		}
+
+		meta := requestmeta.FromContext(ctx)
+		err = h.audit.AppendAuditEntry(ctx, domain.AuditEntry{
+			Action:       domain.AuditActionSetPackSizes,
+			Actor:        meta.Actor,
+			ClaimedActor: meta.ClaimedActor,
+			SourceIP:     meta.SourceIP,
+			RequestID:    meta.RequestID,
+			Before:       before,
+			After:        cmd.Sizes,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Written in the same transaction, so the event exists if and only if
+		// the change committed.
+		event, err := domain.NewPackSizesChangedEvent(domain.PackSizesChanged{
+			Before:     domain.NewEventPacks(before),
+			After:      domain.NewEventPacks(cmd.Sizes),
+			Actor:      meta.Actor,
+			RequestID:  meta.RequestID,
+			OccurredAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		return h.outbox.AppendOutboxEvent(ctx, event)
+	})
+}
+
+type invalidatingSetPackSizesHandler struct {
+	base       SetPackSizesHandler
+	invalidate func()
+}
+
+// NewInvalidatingSetPackSizesHandler calls invalidate after every pack-size
+// change this process commits, so its own reads never see a stale cache.
+func NewInvalidatingSetPackSizesHandler(base SetPackSizesHandler, invalidate func()) SetPackSizesHandler {
+	return &invalidatingSetPackSizesHandler{base: base, invalidate: invalidate}
+}
+
+func (h *invalidatingSetPackSizesHandler) Handle(ctx context.Context, cmd *SetPackSizesCommand) error {
+	if err := h.base.Handle(ctx, cmd); err != nil {
+		return err
+	}
+	if !cmd.DryRun {
+		h.invalidate()
+	}
+	return nil
 }
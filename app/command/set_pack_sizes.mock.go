@@ -36,11 +36,12 @@ func (m *MockSetPackSizesRepository) EXPECT() *MockSetPackSizesRepositoryMockRec
 }
 
 // SetPackSizes mocks base method.
-func (m *MockSetPackSizesRepository) SetPackSizes(ctx context.Context, sizes []domain.SmartPack) error {
+func (m *MockSetPackSizesRepository) SetPackSizes(ctx context.Context, sizes []domain.SmartPack) ([]domain.SmartPack, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetPackSizes", ctx, sizes)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]domain.SmartPack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // SetPackSizes indicates an expected call of SetPackSizes.
@@ -48,3 +49,77 @@ func (mr *MockSetPackSizesRepositoryMockRecorder) SetPackSizes(ctx, sizes interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPackSizes", reflect.TypeOf((*MockSetPackSizesRepository)(nil).SetPackSizes), ctx, sizes)
 }
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendAuditEntry mocks base method.
+func (m *MockAuditRepository) AppendAuditEntry(ctx context.Context, entry domain.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAuditEntry indicates an expected call of AppendAuditEntry.
+func (mr *MockAuditRepositoryMockRecorder) AppendAuditEntry(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAuditEntry", reflect.TypeOf((*MockAuditRepository)(nil).AppendAuditEntry), ctx, entry)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendOutboxEvent mocks base method.
+func (m *MockOutboxRepository) AppendOutboxEvent(ctx context.Context, event domain.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendOutboxEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendOutboxEvent indicates an expected call of AppendOutboxEvent.
+func (mr *MockOutboxRepositoryMockRecorder) AppendOutboxEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendOutboxEvent", reflect.TypeOf((*MockOutboxRepository)(nil).AppendOutboxEvent), ctx, event)
+}
@@ -0,0 +1,9 @@
+package command
+
+import "context"
+
+// Transactor runs the repository calls of a command atomically. Repositories
+// called with the ctx passed to fn take part in the same transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// WebhookSubscriptionsCache is a read-through cache around the subscriptions
+// of an EnqueueWebhookEventRepository, so queueing an event does not query
+// them every time. Changes made by this process call Invalidate; those of
+// other replicas are picked up once the ttl expires. A ttl of zero keeps the
+// subscriptions until Invalidate is called.
+type WebhookSubscriptionsCache struct {
+	EnqueueWebhookEventRepository
+	ttl time.Duration
+	now func() time.Time
+
+	mu         sync.RWMutex
+	subs       []domain.WebhookSubscription
+	loadedAt   time.Time
+	cached     bool
+	generation uint64
+}
+
+func NewWebhookSubscriptionsCache(repo EnqueueWebhookEventRepository, ttl time.Duration) *WebhookSubscriptionsCache {
+	return &WebhookSubscriptionsCache{EnqueueWebhookEventRepository: repo, ttl: ttl, now: time.Now}
+}
+
+func (c *WebhookSubscriptionsCache) ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	c.mu.RLock()
+	subs, cached, loadedAt, generation := c.subs, c.cached, c.loadedAt, c.generation
+	c.mu.RUnlock()
+	if cached && (c.ttl <= 0 || c.now().Sub(loadedAt) < c.ttl) {
+		return slices.Clone(subs), nil
+	}
+
+	loadedAt = c.now()
+	subs, err := c.EnqueueWebhookEventRepository.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// An invalidation that raced with the load may have seen newer data;
+	// only cache the result if none happened.
+	c.mu.Lock()
+	if c.generation == generation {
+		c.subs, c.cached, c.loadedAt = slices.Clone(subs), true, loadedAt
+	}
+	c.mu.Unlock()
+
+	return subs, nil
+}
+
+// Invalidate drops the cached subscriptions so the next list reloads them.
+func (c *WebhookSubscriptionsCache) Invalidate() {
+	c.mu.Lock()
+	c.subs, c.cached = nil, false
+	c.generation++
+	c.mu.Unlock()
+}
+
+type invalidatingCommandHandler[C any] struct {
+	base       decorator.CommandHandler[C]
+	invalidate func()
+}
+
+// NewInvalidatingRegisterWebhookHandler calls invalidate after every
+// subscription this process registers.
+func NewInvalidatingRegisterWebhookHandler(base RegisterWebhookHandler, invalidate func()) RegisterWebhookHandler {
+	return &invalidatingCommandHandler[*RegisterWebhookCommand]{base: base, invalidate: invalidate}
+}
+
+// NewInvalidatingDeleteWebhookHandler calls invalidate after every
+// subscription this process deletes.
+func NewInvalidatingDeleteWebhookHandler(base DeleteWebhookHandler, invalidate func()) DeleteWebhookHandler {
+	return &invalidatingCommandHandler[*DeleteWebhookCommand]{base: base, invalidate: invalidate}
+}
+
+func (h *invalidatingCommandHandler[C]) Handle(ctx context.Context, cmd C) error {
+	if err := h.base.Handle(ctx, cmd); err != nil {
+		return err
+	}
+	h.invalidate()
+	return nil
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscriptionsCache(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockEnqueueWebhookEventRepository(gomock.NewController(t))
+	cache := NewWebhookSubscriptionsCache(repo, time.Minute)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	first := []domain.WebhookSubscription{{ID: 1}}
+	repo.EXPECT().ListWebhookSubscriptions(gomock.Any()).Return(first, nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		subs, err := cache.ListWebhookSubscriptions(ctx)
+		require.NoError(t, err)
+		require.Equal(t, first, subs)
+	}
+
+	cache.Invalidate()
+	second := []domain.WebhookSubscription{{ID: 1}, {ID: 2}}
+	repo.EXPECT().ListWebhookSubscriptions(gomock.Any()).Return(second, nil).Times(1)
+
+	subs, err := cache.ListWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, second, subs)
+
+	// Subscriptions changed by other replicas show up once the ttl expires.
+	now = now.Add(time.Minute)
+	repo.EXPECT().ListWebhookSubscriptions(gomock.Any()).Return(first, nil).Times(1)
+
+	subs, err = cache.ListWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, first, subs)
+}
+
+func TestWebhookSubscriptionsCacheSkipsRacingLoad(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockEnqueueWebhookEventRepository(gomock.NewController(t))
+	cache := NewWebhookSubscriptionsCache(repo, 0)
+
+	repo.EXPECT().ListWebhookSubscriptions(gomock.Any()).
+		DoAndReturn(func(context.Context) ([]domain.WebhookSubscription, error) {
+			cache.Invalidate()
+			return nil, nil
+		}).
+		Times(1)
+	repo.EXPECT().ListWebhookSubscriptions(gomock.Any()).
+		Return([]domain.WebhookSubscription{{ID: 1}}, nil).
+		Times(1)
+
+	_, err := cache.ListWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+
+	subs, err := cache.ListWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+}
+
+func TestInvalidatingWebhookHandlers(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	registerRepo := NewMockRegisterWebhookRepository(ctrl)
+	deleteRepo := NewMockDeleteWebhookRepository(ctrl)
+
+	invalidated := 0
+	invalidate := func() { invalidated++ }
+	register := NewInvalidatingRegisterWebhookHandler(NewRegisterWebhookHandler(registerRepo), invalidate)
+	del := NewInvalidatingDeleteWebhookHandler(NewDeleteWebhookHandler(deleteRepo), invalidate)
+
+	err := register.Handle(ctx, &RegisterWebhookCommand{URL: "ftp://example.com"})
+	require.Error(t, err)
+	require.Zero(t, invalidated, "a rejected subscription changes nothing")
+
+	registerRepo.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+			sub.ID = 1
+			return sub, nil
+		})
+	require.NoError(t, register.Handle(ctx, &RegisterWebhookCommand{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{domain.EventCalculationCompleted},
+	}))
+	require.Equal(t, 1, invalidated)
+
+	deleteRepo.EXPECT().DeleteWebhookSubscription(gomock.Any(), int64(1)).Return(nil)
+	require.NoError(t, del.Handle(ctx, &DeleteWebhookCommand{ID: 1}))
+	require.Equal(t, 2, invalidated)
+}
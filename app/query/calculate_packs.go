@@ -0,0 +1,82 @@
+package query
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type CalculatePacksQuery struct {
+	ItemsOrdered int
+}
+
+//go:generate mockgen -package=query -destination=calculate_packs.mock.go -source=calculate_packs.go
+type PackCalculator interface {
+	Calculate(ctx context.Context, order int, packSizes []int) (*domain.PackSolution, error)
+}
+
+type CalculatePacksHandler decorator.QueryHandler[*CalculatePacksQuery, *domain.PackSolution]
+
+type calculatePacksHandler struct {
+	packSizes  GetPackSizesHandler
+	calculator PackCalculator
+	limits     domain.InputLimits
+}
+
+// NewCalculatePacksHandler reads the pack sizes through packSizes, so the
+// calculation uses the pack-size cache when it is enabled.
+func NewCalculatePacksHandler(packSizes GetPackSizesHandler, calculator PackCalculator, limits domain.InputLimits) CalculatePacksHandler {
+	return decorator.ApplyAuthorizedQueryDecorators[*CalculatePacksQuery, *domain.PackSolution](&calculatePacksHandler{
+		packSizes:  packSizes,
+		calculator: calculator,
+		limits:     limits,
+	}, domain.PermissionCalculatePacks)
+}
+
+// Handle solves the order with the enabled pack sizes and attaches their
+// label, SKU and GTIN to the result. Both the order and the pack sizes are
+// checked against the limits, since the calculation grows with each.
+func (h *calculatePacksHandler) Handle(ctx context.Context, q *CalculatePacksQuery) (*domain.PackSolution, error) {
+	if err := h.limits.ValidateItemsOrdered(q.ItemsOrdered); err != nil {
+		return nil, err
+	}
+
+	packs, err := h.packSizes.Handle(ctx, &GetPackSizesQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := domain.EnabledPackSizes(packs)
+	if err := h.limits.ValidateEnabledPackSizes(sizes); err != nil {
+		return nil, err
+	}
+
+	result, err := h.calculator.Calculate(ctx, q.ItemsOrdered, sizes)
+	if err != nil {
+		return nil, err
+	}
+	result.ApplyPackMetadata(packs)
+	return result, nil
+}
+
+type notifyingCalculatePacksHandler struct {
+	base   CalculatePacksHandler
+	notify func(ctx context.Context, result *domain.PackSolution)
+}
+
+// NewNotifyingCalculatePacksHandler calls notify with every result of base,
+// so a calculation is reported the same way whether it came from HTTP, the
+// CLI or the worker.
+func NewNotifyingCalculatePacksHandler(base CalculatePacksHandler, notify func(ctx context.Context, result *domain.PackSolution)) CalculatePacksHandler {
+	return &notifyingCalculatePacksHandler{base: base, notify: notify}
+}
+
+func (h *notifyingCalculatePacksHandler) Handle(ctx context.Context, q *CalculatePacksQuery) (*domain.PackSolution, error) {
+	result, err := h.base.Handle(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	h.notify(ctx, result)
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatePacks(t *testing.T) {
+	ctx := context.Background()
+	packs := []domain.SmartPack{
+		{Size: 500, Label: "Box", SKU: "BOX-500", Enabled: true},
+		{Size: 250, Enabled: true},
+		{Size: 100, Enabled: false},
+	}
+
+	t.Run("non-positive order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		handler := NewCalculatePacksHandler(
+			NewGetPackSizesHandler(NewMockGetPackSizesRepository(ctrl)),
+			NewMockPackCalculator(ctrl),
+			domain.DefaultInputLimits,
+		)
+
+		_, err := handler.Handle(ctx, &CalculatePacksQuery{ItemsOrdered: 0})
+		require.ErrorIs(t, err, domain.ErrInvalidItemsOrdered)
+	})
+
+	t.Run("order above the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		handler := NewCalculatePacksHandler(
+			NewGetPackSizesHandler(NewMockGetPackSizesRepository(ctrl)),
+			NewMockPackCalculator(ctrl),
+			domain.InputLimits{MaxItemsOrdered: 1000},
+		)
+
+		_, err := handler.Handle(ctx, &CalculatePacksQuery{ItemsOrdered: 1001})
+		require.ErrorIs(t, err, domain.ErrItemsOrderedLimit)
+	})
+
+	t.Run("stored pack sizes above the limits", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			limits domain.InputLimits
+			want   error
+		}{
+			{name: "size", limits: domain.InputLimits{MaxPackSize: 300}, want: domain.ErrPackSizeLimit},
+			{name: "count", limits: domain.InputLimits{MaxPackSizes: 1}, want: domain.ErrPackSizesLimit},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				repo := NewMockGetPackSizesRepository(ctrl)
+				repo.EXPECT().GetPackSizes(gomock.Any()).Return(packs, nil)
+				handler := NewCalculatePacksHandler(NewGetPackSizesHandler(repo), NewMockPackCalculator(ctrl), tc.limits)
+
+				_, err := handler.Handle(ctx, &CalculatePacksQuery{ItemsOrdered: 100})
+				require.ErrorIs(t, err, tc.want)
+			})
+		}
+	})
+
+	t.Run("pack sizes cannot be loaded", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := NewMockGetPackSizesRepository(ctrl)
+		handler := NewCalculatePacksHandler(NewGetPackSizesHandler(repo), NewMockPackCalculator(ctrl), domain.DefaultInputLimits)
+
+		repo.EXPECT().GetPackSizes(gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		_, err := handler.Handle(ctx, &CalculatePacksQuery{ItemsOrdered: 251})
+		require.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("solves with the enabled sizes and attaches metadata", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repo := NewMockGetPackSizesRepository(ctrl)
+		calculator := NewMockPackCalculator(ctrl)
+		handler := NewCalculatePacksHandler(NewGetPackSizesHandler(repo), calculator, domain.DefaultInputLimits)
+
+		repo.EXPECT().GetPackSizes(gomock.Any()).Return(packs, nil)
+		calculator.EXPECT().Calculate(gomock.Any(), 251, []int{500, 250}).Return(&domain.PackSolution{
+			ItemsOrdered: 251,
+			TotalItems:   500,
+			TotalPacks:   1,
+			Packs:        map[int]int{500: 1},
+			PackDetails:  []domain.PackDetail{{Size: 500, Quantity: 1}},
+		}, nil)
+
+		result, err := handler.Handle(ctx, &CalculatePacksQuery{ItemsOrdered: 251})
+		require.NoError(t, err)
+		require.Equal(t, []domain.PackDetail{{Size: 500, Quantity: 1, Label: "Box", SKU: "BOX-500"}}, result.PackDetails)
+	})
+}
+
+func TestNotifyingCalculatePacks(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repo := NewMockGetPackSizesRepository(ctrl)
+	calculator := NewMockPackCalculator(ctrl)
+
+	var notified []*domain.PackSolution
+	handler := NewNotifyingCalculatePacksHandler(
+		NewCalculatePacksHandler(NewGetPackSizesHandler(repo), calculator, domain.DefaultInputLimits),
+		func(_ context.Context, result *domain.PackSolution) { notified = append(notified, result) },
+	)
+
+	_, err := handler.Handle(ctx, &CalculatePacksQuery{ItemsOrdered: 0})
+	require.Error(t, err)
+	require.Empty(t, notified, "failed calculations are not reported")
+
+	repo.EXPECT().GetPackSizes(gomock.Any()).Return([]domain.SmartPack{{Size: 250, Enabled: true}}, nil)
+	calculator.EXPECT().Calculate(gomock.Any(), 100, []int{250}).Return(&domain.PackSolution{
+		ItemsOrdered: 100,
+		TotalItems:   250,
+		TotalPacks:   1,
+	}, nil)
+
+	result, err := handler.Handle(ctx, &CalculatePacksQuery{ItemsOrdered: 100})
+	require.NoError(t, err)
+	require.Equal(t, []*domain.PackSolution{result}, notified)
+}
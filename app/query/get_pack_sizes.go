@@ -22,9 +22,9 @@ type getPackSizesHandler struct {
 }
 
 func NewGetPackSizesHandler(repo GetPackSizesRepository) GetPackSizesHandler {
-	return decorator.ApplyQueryDecorators[*GetPackSizesQuery, []domain.SmartPack](&getPackSizesHandler{
+	return decorator.ApplyAuthorizedQueryDecorators[*GetPackSizesQuery, []domain.SmartPack](&getPackSizesHandler{
 		repo: repo,
-	})
+	}, domain.PermissionReadPackSizes)
 }
 
 func (h *getPackSizesHandler) Handle(ctx context.Context, q *GetPackSizesQuery) ([]domain.SmartPack, error) {
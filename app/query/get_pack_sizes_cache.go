@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// PackSizesCache is a read-through cache around GetPackSizesHandler. The
+// active set is loaded on first use and kept until Invalidate is called.
+type PackSizesCache struct {
+	base GetPackSizesHandler
+
+	mu         sync.RWMutex
+	sizes      []domain.SmartPack
+	cached     bool
+	generation uint64
+}
+
+func NewPackSizesCache(base GetPackSizesHandler) *PackSizesCache {
+	return &PackSizesCache{base: base}
+}
+
+func (c *PackSizesCache) Handle(ctx context.Context, q *GetPackSizesQuery) ([]domain.SmartPack, error) {
+	// Hits never reach base, so they need their own check.
+	if err := decorator.Authorize(ctx, domain.PermissionReadPackSizes); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	sizes, cached, generation := c.sizes, c.cached, c.generation
+	c.mu.RUnlock()
+	if cached {
+		return slices.Clone(sizes), nil
+	}
+
+	sizes, err := c.base.Handle(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	// An invalidation that raced with the load may have seen newer data;
+	// only cache the result if none happened.
+	c.mu.Lock()
+	if c.generation == generation {
+		c.sizes, c.cached = slices.Clone(sizes), true
+	}
+	c.mu.Unlock()
+
+	return sizes, nil
+}
+
+// Invalidate drops the cached set so the next Handle reloads it.
+func (c *PackSizesCache) Invalidate() {
+	c.mu.Lock()
+	c.sizes, c.cached = nil, false
+	c.generation++
+	c.mu.Unlock()
+}
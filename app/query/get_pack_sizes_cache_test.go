@@ -0,0 +1,83 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/common/requestmeta"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackSizesCache(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockGetPackSizesRepository(gomock.NewController(t))
+	cache := NewPackSizesCache(NewGetPackSizesHandler(repo))
+
+	repo.EXPECT().GetPackSizes(gomock.Any()).
+		Return([]domain.SmartPack{{Size: 250, Enabled: true}}, nil).
+		Times(1)
+
+	for i := 0; i < 3; i++ {
+		sizes, err := cache.Handle(ctx, &GetPackSizesQuery{})
+		require.NoError(t, err)
+		require.Equal(t, []domain.SmartPack{{Size: 250, Enabled: true}}, sizes)
+	}
+
+	cache.Invalidate()
+	repo.EXPECT().GetPackSizes(gomock.Any()).
+		Return([]domain.SmartPack{{Size: 500, Enabled: true}}, nil).
+		Times(1)
+
+	sizes, err := cache.Handle(ctx, &GetPackSizesQuery{})
+	require.NoError(t, err)
+	require.Equal(t, []domain.SmartPack{{Size: 500, Enabled: true}}, sizes)
+
+	// Callers may modify the returned slice without affecting the cache.
+	sizes[0].Size = 1
+	sizes, err = cache.Handle(ctx, &GetPackSizesQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 500, sizes[0].Size)
+}
+
+func TestPackSizesCacheSkipsRacingLoad(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockGetPackSizesRepository(gomock.NewController(t))
+	cache := NewPackSizesCache(NewGetPackSizesHandler(repo))
+
+	repo.EXPECT().GetPackSizes(gomock.Any()).
+		DoAndReturn(func(context.Context) ([]domain.SmartPack, error) {
+			cache.Invalidate()
+			return []domain.SmartPack{{Size: 250, Enabled: true}}, nil
+		}).
+		Times(1)
+	repo.EXPECT().GetPackSizes(gomock.Any()).
+		Return([]domain.SmartPack{{Size: 500, Enabled: true}}, nil).
+		Times(1)
+
+	_, err := cache.Handle(ctx, &GetPackSizesQuery{})
+	require.NoError(t, err)
+
+	sizes, err := cache.Handle(ctx, &GetPackSizesQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 500, sizes[0].Size)
+}
+
+func TestPackSizesCacheAuthorizesHits(t *testing.T) {
+	repo := NewMockGetPackSizesRepository(gomock.NewController(t))
+	cache := NewPackSizesCache(NewGetPackSizesHandler(repo))
+
+	repo.EXPECT().GetPackSizes(gomock.Any()).
+		Return([]domain.SmartPack{{Size: 250, Enabled: true}}, nil).
+		Times(1)
+
+	_, err := cache.Handle(context.Background(), &GetPackSizesQuery{})
+	require.NoError(t, err)
+
+	ctx := requestmeta.WithMetadata(context.Background(), requestmeta.Metadata{
+		Principal: &domain.Principal{Subject: "nobody"},
+	})
+	_, err = cache.Handle(ctx, &GetPackSizesQuery{})
+	require.ErrorIs(t, err, domain.ErrForbidden)
+}
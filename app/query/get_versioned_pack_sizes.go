@@ -0,0 +1,34 @@
+package query
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type GetVersionedPackSizesQuery struct {
+}
+
+//go:generate mockgen -package=query -destination=get_versioned_pack_sizes.mock.go -source=get_versioned_pack_sizes.go
+type GetVersionedPackSizesRepository interface {
+	// GetVersionedPackSizes returns the active set together with its
+	// version, read consistently with each other.
+	GetVersionedPackSizes(ctx context.Context) (domain.VersionedPackSizes, error)
+}
+
+type GetVersionedPackSizesHandler decorator.QueryHandler[*GetVersionedPackSizesQuery, domain.VersionedPackSizes]
+
+type getVersionedPackSizesHandler struct {
+	repo GetVersionedPackSizesRepository
+}
+
+func NewGetVersionedPackSizesHandler(repo GetVersionedPackSizesRepository) GetVersionedPackSizesHandler {
+	return decorator.ApplyAuthorizedQueryDecorators[*GetVersionedPackSizesQuery, domain.VersionedPackSizes](&getVersionedPackSizesHandler{
+		repo: repo,
+	}, domain.PermissionReadPackSizes)
+}
+
+func (h *getVersionedPackSizesHandler) Handle(ctx context.Context, q *GetVersionedPackSizesQuery) (domain.VersionedPackSizes, error) {
+	return h.repo.GetVersionedPackSizes(ctx)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: get_versioned_pack_sizes.go
+
+// Package query is a generated GoMock package.
+package query
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/rossi1/smart-pack/domain"
+)
+
+// MockGetVersionedPackSizesRepository is a mock of GetVersionedPackSizesRepository interface.
+type MockGetVersionedPackSizesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetVersionedPackSizesRepositoryMockRecorder
+}
+
+// MockGetVersionedPackSizesRepositoryMockRecorder is the mock recorder for MockGetVersionedPackSizesRepository.
+type MockGetVersionedPackSizesRepositoryMockRecorder struct {
+	mock *MockGetVersionedPackSizesRepository
+}
+
+// NewMockGetVersionedPackSizesRepository creates a new mock instance.
+func NewMockGetVersionedPackSizesRepository(ctrl *gomock.Controller) *MockGetVersionedPackSizesRepository {
+	mock := &MockGetVersionedPackSizesRepository{ctrl: ctrl}
+	mock.recorder = &MockGetVersionedPackSizesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetVersionedPackSizesRepository) EXPECT() *MockGetVersionedPackSizesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetVersionedPackSizes mocks base method.
+func (m *MockGetVersionedPackSizesRepository) GetVersionedPackSizes(ctx context.Context) (domain.VersionedPackSizes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionedPackSizes", ctx)
+	ret0, _ := ret[0].(domain.VersionedPackSizes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersionedPackSizes indicates an expected call of GetVersionedPackSizes.
+func (mr *MockGetVersionedPackSizesRepositoryMockRecorder) GetVersionedPackSizes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionedPackSizes", reflect.TypeOf((*MockGetVersionedPackSizesRepository)(nil).GetVersionedPackSizes), ctx)
+}
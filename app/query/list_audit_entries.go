@@ -0,0 +1,48 @@
+package query
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type ListAuditEntriesQuery struct {
+	Filter domain.AuditFilter
+}
+
+//go:generate mockgen -package=query -destination=list_audit_entries.mock.go -source=list_audit_entries.go
+type AuditRepository interface {
+	ListAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+}
+
+type ListAuditEntriesHandler decorator.QueryHandler[*ListAuditEntriesQuery, []domain.AuditEntry]
+
+type listAuditEntriesHandler struct {
+	repo AuditRepository
+}
+
+func NewListAuditEntriesHandler(repo AuditRepository) ListAuditEntriesHandler {
+	return decorator.ApplyAuthorizedQueryDecorators[*ListAuditEntriesQuery, []domain.AuditEntry](&listAuditEntriesHandler{
+		repo: repo,
+	}, domain.PermissionReadAudit)
+}
+
+func (h *listAuditEntriesHandler) Handle(ctx context.Context, q *ListAuditEntriesQuery) ([]domain.AuditEntry, error) {
+	filter := q.Filter
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return h.repo.ListAuditEntries(ctx, filter)
+}
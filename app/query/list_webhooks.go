@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/common/decorator"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+const (
+	defaultWebhookDeliveryLimit = 100
+	maxWebhookDeliveryLimit     = 1000
+)
+
+type ListWebhooksQuery struct{}
+
+type ListWebhookDeliveriesQuery struct {
+	Filter domain.WebhookDeliveryFilter
+}
+
+//go:generate mockgen -package=query -destination=list_webhooks.mock.go -source=list_webhooks.go
+type WebhookRepository interface {
+	ListWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// ListWebhookDeliveries returns the deliveries matching filter, newest
+	// first, or domain.ErrWebhookNotFound for an unknown subscription.
+	ListWebhookDeliveries(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+}
+
+type ListWebhooksHandler decorator.QueryHandler[*ListWebhooksQuery, []domain.WebhookSubscription]
+
+type listWebhooksHandler struct {
+	repo WebhookRepository
+}
+
+func NewListWebhooksHandler(repo WebhookRepository) ListWebhooksHandler {
+	return decorator.ApplyAuthorizedQueryDecorators[*ListWebhooksQuery, []domain.WebhookSubscription](&listWebhooksHandler{
+		repo: repo,
+	}, domain.PermissionManageWebhooks)
+}
+
+func (h *listWebhooksHandler) Handle(ctx context.Context, _ *ListWebhooksQuery) ([]domain.WebhookSubscription, error) {
+	return h.repo.ListWebhookSubscriptions(ctx)
+}
+
+type ListWebhookDeliveriesHandler decorator.QueryHandler[*ListWebhookDeliveriesQuery, []domain.WebhookDelivery]
+
+type listWebhookDeliveriesHandler struct {
+	repo WebhookRepository
+}
+
+func NewListWebhookDeliveriesHandler(repo WebhookRepository) ListWebhookDeliveriesHandler {
+	return decorator.ApplyAuthorizedQueryDecorators[*ListWebhookDeliveriesQuery, []domain.WebhookDelivery](&listWebhookDeliveriesHandler{
+		repo: repo,
+	}, domain.PermissionManageWebhooks)
+}
+
+func (h *listWebhookDeliveriesHandler) Handle(ctx context.Context, q *ListWebhookDeliveriesQuery) ([]domain.WebhookDelivery, error) {
+	filter := q.Filter
+	if filter.Limit <= 0 {
+		filter.Limit = defaultWebhookDeliveryLimit
+	}
+	if filter.Limit > maxWebhookDeliveryLimit {
+		filter.Limit = maxWebhookDeliveryLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return h.repo.ListWebhookDeliveries(ctx, filter)
+}
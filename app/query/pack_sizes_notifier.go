@@ -0,0 +1,43 @@
+package query
+
+import "sync"
+
+// PackSizesNotifier tells subscribers that the pack sizes may have changed.
+// Notifications carry no data and coalesce: a subscriber that is busy sees a
+// burst of changes as one, and reads the latest set when it gets to it.
+type PackSizesNotifier struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func NewPackSizesNotifier() *PackSizesNotifier {
+	return &PackSizesNotifier{subscribers: make(map[chan struct{}]struct{})}
+}
+
+// Subscribe returns a channel that receives after every Notify, and a
+// function that cancels the subscription.
+func (n *PackSizesNotifier) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	n.subscribers[ch] = struct{}{}
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		delete(n.subscribers, ch)
+		n.mu.Unlock()
+	}
+}
+
+func (n *PackSizesNotifier) Notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackSizesNotifier(t *testing.T) {
+	notifier := NewPackSizesNotifier()
+
+	first, unsubscribeFirst := notifier.Subscribe()
+	second, unsubscribeSecond := notifier.Subscribe()
+	defer unsubscribeSecond()
+
+	// A burst of changes reaches each subscriber once.
+	notifier.Notify()
+	notifier.Notify()
+
+	require.Len(t, first, 1)
+	require.Len(t, second, 1)
+	<-first
+	<-second
+
+	unsubscribeFirst()
+	notifier.Notify()
+
+	require.Len(t, first, 0)
+	require.Len(t, second, 1)
+}
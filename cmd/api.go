@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -11,8 +13,9 @@ import (
 	"github.com/rossi1/smart-pack/app/command"
 	"github.com/rossi1/smart-pack/app/query"
 	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/metrics"
 	"github.com/rossi1/smart-pack/pkg/server"
-	"github.com/rossi1/smart-pack/ports"
 	"github.com/rossi1/smart-pack/ports/rest"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -43,12 +46,31 @@ func init() {
 }
 
 func startHTTP(ctx context.Context, cfg *appConfig.AppConfig) {
+	stopTracing := startTracing(ctx, cfg)
+	defer stopTracing()
+
 	deps := initializeDependencies(ctx, cfg)
 	defer safelyCloseDependencies(ctx, deps)
 
+	if err := prepareSchema(ctx, cfg, deps); err != nil {
+		logrus.WithContext(ctx).WithError(err).Fatal("Database schema is not ready")
+	}
+
 	application := NewApplication(ctx, cfg, deps)
+	if cfg.MetricsEnabled && application.DatabaseHealth != nil {
+		metrics.RegisterDatabaseStats(application.DatabaseHealth.Stats)
+	}
+
+	stopPurge := startPurgeJob(ctx, cfg, application)
+	defer stopPurge()
+
+	stopRelay := startOutboxRelay(ctx, cfg, application)
+	defer stopRelay()
+
+	stopWebhooks := startWebhookDelivery(ctx, cfg, application)
+	defer stopWebhooks()
 
-	startRestServer(ctx, cfg, application)
+	startRestServer(ctx, cfg, deps, application)
 }
 
 func initializeDependencies(ctx context.Context, cfg *appConfig.AppConfig) *Dependencies {
@@ -59,8 +81,73 @@ func initializeDependencies(ctx context.Context, cfg *appConfig.AppConfig) *Depe
 	return deps
 }
 
+// initializeCLIDependencies opens only the storage and the pack sizes file,
+// for commands that run one operation and exit. Nothing runs in the
+// background: the file is not watched, pack-size changes are not listened
+// for and outbox events are left for `api` to relay.
+func initializeCLIDependencies(ctx context.Context, cfg *appConfig.AppConfig) *Dependencies {
+	deps := newStorageDependencies(ctx, cfg)
+	if cfg.PackSizesFile != "" {
+		deps.PackSizesFile = loadPackSizesFile(ctx, cfg)
+	}
+	return deps
+}
+
 func newDependencies(rootCtx context.Context, cfg *appConfig.AppConfig) *Dependencies {
-	pgDB, err := createPostgresConnection(rootCtx, cfg)
+	deps := newStorageDependencies(rootCtx, cfg)
+
+	if cfg.PackSizesFile != "" {
+		packSizesFile := loadPackSizesFile(rootCtx, cfg)
+		if err := packSizesFile.Watch(); err != nil {
+			logrus.WithContext(rootCtx).Fatal("Error while watching pack sizes file", err)
+		}
+		deps.PackSizesFile = packSizesFile
+	}
+
+	// Only Postgres is shared between replicas; the other sources are either
+	// local to this process or watched by the file repository.
+	if deps.DB != nil && deps.PackSizesFile == nil {
+		deps.PackSizesListener = adapters.NewPostgresListener(deps.DB, adapters.PackSizesChannel)
+		deps.PackSizesListener.Start(rootCtx)
+	}
+
+	publisher, err := newOutboxPublisher(cfg)
+	if err != nil {
+		logrus.WithContext(rootCtx).Fatal("Error while creating outbox publisher", err)
+	}
+	deps.OutboxPublisher = publisher
+
+	return deps
+}
+
+func loadPackSizesFile(ctx context.Context, cfg *appConfig.AppConfig) *adapters.FilePackSizesRepository {
+	packSizesFile, err := adapters.NewFilePackSizesRepository(cfg.PackSizesFile, inputLimits(cfg))
+	if err != nil {
+		logrus.WithContext(ctx).Fatal("Error while loading pack sizes file", err)
+	}
+	return packSizesFile
+}
+
+func newStorageDependencies(rootCtx context.Context, cfg *appConfig.AppConfig) *Dependencies {
+	switch storageDriver(cfg) {
+	case appConfig.StorageDriverMemory:
+		return &Dependencies{
+			Memory: adapters.NewMemoryStore(),
+		}
+	case appConfig.StorageDriverSQLite:
+		sqliteDB, err := adapters.OpenSQLite(rootCtx, cfg.DatabaseURL)
+		if err != nil {
+			logrus.WithContext(rootCtx).Fatal("Error while opening sqlite database", err)
+		}
+		return &Dependencies{
+			SQLite: sqliteDB,
+		}
+	case appConfig.StorageDriverPostgres:
+	default:
+		logrus.WithContext(rootCtx).Fatalf("Unknown storage driver %q", cfg.StorageDriver)
+	}
+
+	pgDB, err := createPostgresPool(rootCtx, cfg)
 	if err != nil {
 		logrus.WithContext(rootCtx).Fatal("Error while connecting to postgres client", err)
 	}
@@ -70,13 +157,51 @@ func newDependencies(rootCtx context.Context, cfg *appConfig.AppConfig) *Depende
 	}
 }
 
+// migrationLockKey is the Postgres advisory lock held while AUTO_MIGRATE
+// runs, so replicas starting together migrate one after another.
+const migrationLockKey int64 = 0x736d6967 // "smig"
+
+// prepareSchema applies pending migrations when AUTO_MIGRATE is set and
+// otherwise refuses to serve from a schema that is behind or dirty.
+func prepareSchema(ctx context.Context, cfg *appConfig.AppConfig, deps *Dependencies) error {
+	if deps.DB == nil && deps.SQLite == nil {
+		return nil
+	}
+
+	m := adapters.NewMigrationManager(cfg.DatabaseURL, migrationPath(cfg))
+	if !cfg.AutoMigrate {
+		return m.CheckSchema()
+	}
+
+	apply := func() error {
+		result, err := m.LoadMigrations()
+		if err == nil && result.From != result.To {
+			logrus.WithContext(ctx).WithField("from", result.From).WithField("to", result.To).Info("Migrated database schema")
+		}
+		return err
+	}
+	if deps.DB == nil {
+		return apply()
+	}
+	return adapters.WithAdvisoryLock(ctx, deps.DB, migrationLockKey, apply)
+}
+
 func safelyCloseDependencies(ctx context.Context, deps *Dependencies) {
 	if err := deps.Close(ctx); err != nil {
 		logrus.Error("Error closing dependencies", err)
 	}
 }
 
-func startRestServer(ctx context.Context, cfg *appConfig.AppConfig, application *app.Application) {
+func startRestServer(ctx context.Context, cfg *appConfig.AppConfig, deps *Dependencies, application *app.Application) {
+	authenticators, err := newAuthenticators(cfg)
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).Fatal("Error while configuring authentication")
+	}
+	rateLimiter, rateLimits, err := newRateLimiter(cfg, deps)
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).Fatal("Error while configuring rate limits")
+	}
+
 	server.RunHTTPServer(
 		ctx,
 		cfg,
@@ -84,28 +209,267 @@ func startRestServer(ctx context.Context, cfg *appConfig.AppConfig, application
 		"/api",
 		rest.SwaggerPath,
 		func(router chi.Router) http.Handler {
-			return ports.HandlerFromMux(rest.NewHTTPServer(application), router)
+			return rest.Handler(application, router, rest.HandlerOptions{
+				Authenticators: authenticators,
+				RateLimiter:    rateLimiter,
+				RateLimits:     rateLimits,
+				MaxBodyBytes:   cfg.MaxRequestBodyBytes,
+			})
 		},
 	)
 }
 
+// newRateLimiter returns no limiter when RATE_LIMIT_ENABLED is off.
+func newRateLimiter(cfg *appConfig.AppConfig, deps *Dependencies) (rest.RateLimiter, rest.RateLimits, error) {
+	if !cfg.RateLimitEnabled {
+		return nil, rest.RateLimits{}, nil
+	}
+
+	limits, err := rest.ParseRateLimits(cfg.RateLimitDefault, cfg.RateLimitRoutes)
+	if err != nil {
+		return nil, rest.RateLimits{}, err
+	}
+	if cfg.RateLimitAuthFailures != "" {
+		if limits.AuthFailures, err = domain.ParseRateLimit(cfg.RateLimitAuthFailures); err != nil {
+			return nil, rest.RateLimits{}, err
+		}
+	}
+
+	switch cfg.RateLimitStore {
+	case appConfig.RateLimitStoreMemory:
+		return adapters.NewMemoryRateLimiter(), limits, nil
+	case appConfig.RateLimitStorePostgres:
+		if deps.DB == nil {
+			return nil, rest.RateLimits{}, errors.New("the postgres rate-limit store needs the postgres storage driver")
+		}
+		return adapters.NewPostgresRateLimiter(deps.DB), limits, nil
+	}
+	return nil, rest.RateLimits{}, fmt.Errorf("unknown rate-limit store %q", cfg.RateLimitStore)
+}
+
+// newAuthenticators returns one authenticator per configured kind of
+// credential, or none when AUTH_ENABLED is off.
+func newAuthenticators(cfg *appConfig.AppConfig) ([]rest.Authenticator, error) {
+	if !cfg.AuthEnabled {
+		return nil, nil
+	}
+
+	var authenticators []rest.Authenticator
+	if cfg.AuthAPIKeys != "" {
+		keys, err := adapters.NewAPIKeyAuthenticator(cfg.AuthAPIKeys)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, keys)
+	}
+	if cfg.AuthJWTHS256Secret != "" || cfg.AuthJWTRS256PublicKeyFile != "" {
+		tokens, err := adapters.NewJWTAuthenticator(adapters.JWTConfig{
+			HS256Secret:        cfg.AuthJWTHS256Secret,
+			RS256PublicKeyFile: cfg.AuthJWTRS256PublicKeyFile,
+			Issuer:             cfg.AuthJWTIssuer,
+			Audience:           cfg.AuthJWTAudience,
+		})
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, tokens)
+	}
+	if len(authenticators) == 0 {
+		return nil, errors.New("AUTH_ENABLED needs AUTH_API_KEYS or a JWT key")
+	}
+	return authenticators, nil
+}
+
+func inputLimits(cfg *appConfig.AppConfig) domain.InputLimits {
+	return domain.InputLimits{
+		MaxItemsOrdered: cfg.MaxItemsOrdered,
+		MaxPackSize:     cfg.MaxPackSize,
+		MaxPackSizes:    cfg.MaxPackSizes,
+	}
+}
+
 func NewApplication(ctx context.Context, cfg *appConfig.AppConfig, deps *Dependencies) *app.Application {
 	logrus.WithContext(ctx).
-		WithField("config", cfg).
+		WithField("config", cfg.Redacted()).
 		Info("Creating application")
 
-	smartPackRepo := adapters.NewSmartPackRepository(deps.DB)
+	repos := newRepositories(deps)
 	packCalculator := smartCalculator.NewPackCalculator()
+	limits := inputLimits(cfg)
+
+	getPackSizes := query.NewGetPackSizesHandler(repos.smartPack)
+	setPackSizes := command.NewSetPackSizesHandler(repos.smartPack, repos.audit, repos.outbox, repos.transactor, limits)
+	if deps.PackSizesListener != nil && cfg.PackSizesCacheEnabled {
+		cache := query.NewPackSizesCache(getPackSizes)
+		deps.PackSizesListener.Subscribe(cache.Invalidate)
+		getPackSizes = cache
+		setPackSizes = command.NewInvalidatingSetPackSizesHandler(setPackSizes, cache.Invalidate)
+	}
+
+	// Streams learn about changes committed here right away, and about those
+	// of other replicas or a reloaded file through the listeners.
+	packSizesChanges := query.NewPackSizesNotifier()
+	setPackSizes = command.NewInvalidatingSetPackSizesHandler(setPackSizes, packSizesChanges.Notify)
+	if deps.PackSizesListener != nil {
+		deps.PackSizesListener.Subscribe(packSizesChanges.Notify)
+	}
+	if deps.PackSizesFile != nil {
+		deps.PackSizesFile.Subscribe(packSizesChanges.Notify)
+	}
+
+	var publishers []command.EventPublisher
+	if deps.OutboxPublisher != nil {
+		publishers = append(publishers, deps.OutboxPublisher)
+	}
+
+	registerWebhook := command.NewRegisterWebhookHandler(repos.webhooks)
+	deleteWebhook := command.NewDeleteWebhookHandler(repos.webhooks)
+	var enqueueWebhookEvent command.EnqueueWebhookEventHandler
+	var deliverWebhooks command.DeliverWebhooksHandler
+	if cfg.WebhooksEnabled {
+		subscriptions := command.NewWebhookSubscriptionsCache(repos.webhooks, cfg.WebhookSubscriptionsTTL)
+		registerWebhook = command.NewInvalidatingRegisterWebhookHandler(registerWebhook, subscriptions.Invalidate)
+		deleteWebhook = command.NewInvalidatingDeleteWebhookHandler(deleteWebhook, subscriptions.Invalidate)
+		enqueueWebhookEvent = command.NewEnqueueWebhookEventHandler(subscriptions)
+		deliverWebhooks = command.NewDeliverWebhooksHandler(repos.webhooks, adapters.NewHTTPWebhookSender(cfg.WebhookTimeout))
+		publishers = append(publishers, webhookEventPublisher{enqueue: enqueueWebhookEvent})
+	}
+
+	calculatePacks := query.NewCalculatePacksHandler(getPackSizes, packCalculator, limits)
+	if enqueueWebhookEvent != nil {
+		calculatePacks = query.NewNotifyingCalculatePacksHandler(calculatePacks, calculationCompletedNotifier{enqueue: enqueueWebhookEvent}.Notify)
+	}
+
+	var relayOutbox command.RelayOutboxHandler
+	if len(publishers) > 0 {
+		relayOutbox = command.NewRelayOutboxHandler(repos.outbox, multiPublisher(publishers))
+	}
+
+	// The schema is checked against the migrations this binary ships, which
+	// are read once here rather than on every probe.
+	var schemaHealth app.SchemaHealth
+	if deps.DB != nil || deps.SQLite != nil {
+		latest, err := adapters.NewMigrationManager(cfg.DatabaseURL, migrationPath(cfg)).LatestVersion()
+		if err != nil {
+			logrus.WithContext(ctx).WithError(err).Fatal("Error while reading migrations")
+		}
+		if deps.DB != nil {
+			schemaHealth = adapters.NewPostgresSchemaHealth(deps.DB, latest)
+		} else {
+			schemaHealth = adapters.NewSQLiteSchemaHealth(deps.SQLite, latest)
+		}
+	}
 
 	return &app.Application{
 		ErrorReporter: nil,
 		AppConfig:     cfg,
 		Commands: &app.Commands{
-			SetPackSizes: command.NewSetPackSizesHandler(smartPackRepo),
+			SetPackSizes:   setPackSizes,
+			PurgePackSizes: command.NewPurgePackSizesHandler(repos.retention, repos.transactor),
+			RelayOutbox:    relayOutbox,
+
+			RegisterWebhook:       registerWebhook,
+			DeleteWebhook:         deleteWebhook,
+			ReplayWebhookDelivery: command.NewReplayWebhookDeliveryHandler(repos.webhooks, repos.transactor),
+			EnqueueWebhookEvent:   enqueueWebhookEvent,
+			DeliverWebhooks:       deliverWebhooks,
 		},
 		Queries: &app.Queries{
-			GetPackSizes: query.NewGetPackSizesHandler(smartPackRepo),
+			GetPackSizes:          getPackSizes,
+			GetVersionedPackSizes: query.NewGetVersionedPackSizesHandler(repos.smartPack),
+			CalculatePacks:        calculatePacks,
+			ListAuditEntries:      query.NewListAuditEntriesHandler(repos.audit),
+			ListWebhooks:          query.NewListWebhooksHandler(repos.webhooks),
+			ListWebhookDeliveries: query.NewListWebhookDeliveriesHandler(repos.webhooks),
 		},
-		PackCalculator: packCalculator,
+		DatabaseHealth:   repos.health,
+		SchemaHealth:     schemaHealth,
+		PackSizesChanges: packSizesChanges,
+	}
+}
+
+type smartPackRepository interface {
+	query.GetPackSizesRepository
+	query.GetVersionedPackSizesRepository
+	command.SetPackSizesRepository
+}
+
+type auditRepository interface {
+	query.AuditRepository
+	command.AuditRepository
+}
+
+type outboxRepository interface {
+	command.OutboxRepository
+	command.RelayOutboxRepository
+}
+
+type webhookRepository interface {
+	command.RegisterWebhookRepository
+	command.DeleteWebhookRepository
+	command.EnqueueWebhookEventRepository
+	command.DeliverWebhooksRepository
+	command.ReplayWebhookDeliveryRepository
+	query.WebhookRepository
+}
+
+type repositories struct {
+	smartPack  smartPackRepository
+	retention  command.PurgePackSizesRepository
+	audit      auditRepository
+	outbox     outboxRepository
+	webhooks   webhookRepository
+	transactor command.Transactor
+	health     app.DatabaseHealth
+}
+
+// newRepositories picks the storage adapters matching the initialised
+// dependencies. A pack sizes file replaces the storage for pack sizes only;
+// everything else stays in the configured storage.
+func newRepositories(deps *Dependencies) repositories {
+	repos := newStorageRepositories(deps)
+	if deps.PackSizesFile != nil {
+		repos.smartPack = deps.PackSizesFile
+	}
+	return repos
+}
+
+// newStorageRepositories uses the in-memory store or SQLite when present,
+// Postgres otherwise.
+func newStorageRepositories(deps *Dependencies) repositories {
+	if deps.Memory != nil {
+		smartPack := adapters.NewMemorySmartPackRepository(deps.Memory)
+		return repositories{
+			smartPack:  smartPack,
+			retention:  smartPack,
+			audit:      adapters.NewMemoryAuditRepository(deps.Memory),
+			outbox:     adapters.NewMemoryOutboxRepository(deps.Memory),
+			webhooks:   adapters.NewMemoryWebhookRepository(deps.Memory),
+			transactor: adapters.NewMemoryTransactor(deps.Memory),
+		}
+	}
+
+	if deps.SQLite != nil {
+		smartPack := adapters.NewSQLiteSmartPackRepository(deps.SQLite)
+		return repositories{
+			smartPack:  smartPack,
+			retention:  smartPack,
+			audit:      adapters.NewSQLiteAuditRepository(deps.SQLite),
+			outbox:     adapters.NewSQLiteOutboxRepository(deps.SQLite),
+			webhooks:   adapters.NewSQLiteWebhookRepository(deps.SQLite),
+			transactor: adapters.NewSQLiteTransactor(deps.SQLite),
+			health:     adapters.NewSQLiteHealth(deps.SQLite),
+		}
+	}
+
+	smartPack := adapters.NewSmartPackRepository(deps.DB)
+	return repositories{
+		smartPack:  smartPack,
+		retention:  smartPack,
+		audit:      adapters.NewAuditRepository(deps.DB),
+		outbox:     adapters.NewOutboxRepository(deps.DB),
+		webhooks:   adapters.NewWebhookRepository(deps.DB),
+		transactor: adapters.NewPostgresTransactor(deps.DB),
+		health:     adapters.NewPostgresHealth(deps.DB),
 	}
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/spf13/cobra"
+)
+
+var calculateCmd = &cobra.Command{
+	Use:   "calculate <items-ordered>",
+	Short: "calculate the packs to ship for an order",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(calculatePacks(cmd.Context(), cmd.OutOrStdout(), args[0]))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(calculateCmd)
+}
+
+func calculatePacks(ctx context.Context, stdout io.Writer, itemsOrdered string) error {
+	items, err := strconv.Atoi(itemsOrdered)
+	if err != nil {
+		return fmt.Errorf("items ordered must be a number: %w", err)
+	}
+
+	deps := initializeCLIDependencies(ctx, cfg)
+	defer safelyCloseDependencies(ctx, deps)
+	application := NewApplication(ctx, cfg, deps)
+
+	result, err := application.Queries.CalculatePacks.Handle(ctx, &query.CalculatePacksQuery{ItemsOrdered: items})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d items ordered, %d items in %d packs\n", result.ItemsOrdered, result.TotalItems, result.TotalPacks)
+	for _, d := range result.PackDetails {
+		line := fmt.Sprintf("%d x %d", d.Quantity, d.Size)
+		if d.Label != "" {
+			line += " " + d.Label
+		}
+		if d.SKU != "" {
+			line += " (" + d.SKU + ")"
+		}
+		fmt.Fprintln(stdout, line)
+	}
+	return nil
+}
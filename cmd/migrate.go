@@ -1,63 +1,161 @@
 package cmd
 
 import (
+	"bufio"
 	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/rossi1/smart-pack/adapters"
+	appConfig "github.com/rossi1/smart-pack/config"
 	"github.com/spf13/cobra"
 )
 
 var (
-	direction string
-)
-
-const (
-	up   = "up"
-	down = "down"
+	downAll   bool
+	assumeYes bool
 )
 
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
-	Short: "postgres migration command",
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 1 {
-			return errors.New("please specify direction: usage migrate up or migrate down")
+	Short: "database migration command",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "apply all pending migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := newMigrationManager().LoadMigrations()
+		checkErr(err)
+		printMigration(cmd.OutOrStdout(), result)
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "roll back the last migration, or all of them with --all",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		m := newMigrationManager()
+		if !downAll {
+			result, err := m.RollbackMigration()
+			checkErr(err)
+			printMigration(cmd.OutOrStdout(), result)
+			return
 		}
-		direction = strings.TrimSpace(args[0])
-		if direction != up && direction != down {
-			return errors.New("not a valid direction: usage migrate up or migrate down")
+
+		if !assumeYes {
+			ok, err := confirm(cmd.InOrStdin(), cmd.OutOrStdout(),
+				"This rolls back every migration and drops all data. Type 'yes' to continue: ")
+			checkErr(err)
+			if !ok {
+				fmt.Fprintln(cmd.OutOrStdout(), "Aborted")
+				return
+			}
 		}
-		return nil
+		result, err := m.RollbackAllMigrations()
+		checkErr(err)
+		printMigration(cmd.OutOrStdout(), result)
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show the current migration version and dirty flag",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		version, dirty, err := newMigrationManager().GetStatus()
+		checkErr(err)
+		fmt.Fprintf(cmd.OutOrStdout(), "version: %d\ndirty: %t\n", version, dirty)
 	},
+}
+
+var migrateGotoCmd = &cobra.Command{
+	Use:   "goto <version>",
+	Short: "migrate up or down to a specific version",
+	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		migrationRepo := adapters.NewMigrationManager(cfg.DatabaseURL, cfg.DatabaseMigrationPath)
+		version, err := strconv.ParseUint(args[0], 10, 32)
+		checkErr(err)
+		result, err := newMigrationManager().MigrateTo(uint(version))
+		checkErr(err)
+		printMigration(cmd.OutOrStdout(), result)
+	},
+}
+
+var migrateForceCmd = &cobra.Command{
+	Use:   "force <version>",
+	Short: "set the version and clear the dirty flag without running migrations",
+	Long: "Set the recorded migration version and clear the dirty flag without running any migration.\n" +
+		"Use it to recover after fixing a migration that failed halfway. -1 means no version.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.Atoi(args[0])
+		checkErr(err)
+		if version < -1 {
+			checkErr(errors.New("version must be -1 or greater"))
+		}
+		checkErr(newMigrationManager().ForceVersion(version))
+		fmt.Fprintf(cmd.OutOrStdout(), "Forced version %d\n", version)
+	},
+}
 
-		switch direction {
-		case up:
-			migrateUp(migrationRepo)
-		case down:
-			migrateDown(migrationRepo)
+var migrateCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "scaffold numbered up and down migration files for Postgres and SQLite",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if cfg.DatabaseMigrationPath == "" {
+			checkErr(errors.New("embedded migrations are read-only: set DATABASE_MIGRATION_PATH to the migrations directory"))
+		}
+		// Both storage drivers are supported, so every migration needs a
+		// Postgres and a SQLite version with the same number.
+		files, err := adapters.CreateMigration(args[0],
+			cfg.DatabaseMigrationPath,
+			filepath.Join(cfg.DatabaseMigrationPath, appConfig.StorageDriverSQLite))
+		checkErr(err)
+		for _, f := range files {
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", f)
 		}
 	},
 }
 
-func migrateUp(m *adapters.MigrationManager) {
-	err := m.LoadMigrations()
-	if err != nil {
-		panic(err)
+func newMigrationManager() *adapters.MigrationManager {
+	return adapters.NewMigrationManager(cfg.DatabaseURL, migrationPath(cfg))
+}
+
+func printMigration(out io.Writer, result adapters.MigrationResult) {
+	if result.From == result.To {
+		fmt.Fprintf(out, "Database schema is already at version %d\n", result.To)
+		return
 	}
-	println("Migration up completed")
+	fmt.Fprintf(out, "Migrated from version %d to %d\n", result.From, result.To)
 }
 
-func migrateDown(m *adapters.MigrationManager) {
-	err := m.RollbackMigration()
-	if err != nil {
-		panic(err)
+func confirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	fmt.Fprint(out, prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
 	}
-	println("Migration down completed")
+	return strings.EqualFold(strings.TrimSpace(answer), "yes"), nil
 }
 
 func init() {
 	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(
+		migrateUpCmd,
+		migrateDownCmd,
+		migrateStatusCmd,
+		migrateGotoCmd,
+		migrateForceCmd,
+		migrateCreateCmd,
+	)
+
+	migrateDownCmd.Flags().BoolVar(&downAll, "all", false, "Roll back every migration")
+	migrateDownCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Do not ask for confirmation")
 }
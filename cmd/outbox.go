@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/app"
+	"github.com/rossi1/smart-pack/app/command"
+	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// newOutboxPublisher returns nil when OUTBOX_PUBLISHER is empty.
+func newOutboxPublisher(cfg *appConfig.AppConfig) (command.EventPublisher, error) {
+	switch cfg.OutboxPublisher {
+	case "":
+		return nil, nil
+	case appConfig.OutboxPublisherLog:
+		return adapters.NewLogPublisher(logrus.StandardLogger()), nil
+	case appConfig.OutboxPublisherWebhook:
+		if cfg.OutboxWebhookURL == "" {
+			return nil, errors.New("OUTBOX_WEBHOOK_URL is required by the webhook publisher")
+		}
+		return adapters.NewWebhookPublisher(cfg.OutboxWebhookURL, cfg.OutboxWebhookTimeout), nil
+	case appConfig.OutboxPublisherNATS:
+		return adapters.NewNATSPublisher(cfg.OutboxNATSURL, cfg.OutboxNATSSubject)
+	}
+	return nil, fmt.Errorf("unknown outbox publisher %q", cfg.OutboxPublisher)
+}
+
+// startOutboxRelay delivers due outbox events every OUTBOX_RELAY_INTERVAL
+// until ctx is done or the returned stop is called.
+func startOutboxRelay(ctx context.Context, cfg *appConfig.AppConfig, application *app.Application) (stop func()) {
+	relay := application.Commands.RelayOutbox
+	if relay == nil || cfg.OutboxRelayInterval <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ticker := time.NewTicker(cfg.OutboxRelayInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				relayOutbox(ctx, relay, cfg.OutboxBatchSize)
+			}
+		}
+	}()
+	return cancel
+}
+
+// relayOutbox keeps claiming batches until one comes back short or empty, so
+// a backlog drains without waiting for the next tick.
+func relayOutbox(ctx context.Context, relay command.RelayOutboxHandler, batchSize int) {
+	for ctx.Err() == nil {
+		cmd := &command.RelayOutboxCommand{Limit: batchSize}
+		if err := relay.Handle(ctx, cmd); err != nil {
+			logrus.WithContext(ctx).WithError(err).Error("Outbox relay failed")
+			return
+		}
+		if cmd.Failed > 0 {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"published": cmd.Published,
+				"failed":    cmd.Failed,
+			}).Warn("Some outbox events could not be published and will be retried")
+		}
+		claimed := cmd.Published + cmd.Failed
+		if claimed == 0 || claimed < batchSize {
+			return
+		}
+	}
+}
+
+// multiPublisher hands every event to each publisher in turn. If one fails,
+// the relay retries the event for all of them, so the others may see it
+// twice.
+func multiPublisher(publishers []command.EventPublisher) command.EventPublisher {
+	if len(publishers) == 1 {
+		return publishers[0]
+	}
+	return publisherList(publishers)
+}
+
+type publisherList []command.EventPublisher
+
+func (l publisherList) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	for _, p := range l {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/common/requestmeta"
+	"github.com/rossi1/smart-pack/pkg/packio"
+	"github.com/spf13/cobra"
+)
+
+const cliActor = "cli"
+
+var (
+	exportFormat string
+	exportOutput string
+	importFormat string
+	importFile   string
+	importDryRun bool
+)
+
+var packSizesCmd = &cobra.Command{
+	Use:   "pack-sizes",
+	Short: "manage pack-size configuration",
+}
+
+var packSizesExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export pack sizes as csv, json or yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(exportPackSizes(cmd.Context(), cmd.OutOrStdout()))
+	},
+}
+
+var packSizesImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "replace pack sizes with the contents of a csv, json or yaml file",
+	Run: func(cmd *cobra.Command, args []string) {
+		checkErr(importPackSizes(cmd.Context(), cmd.OutOrStdout()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(packSizesCmd)
+	packSizesCmd.AddCommand(packSizesExportCmd, packSizesImportCmd)
+
+	packSizesExportCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "File format: csv, json or yaml")
+	packSizesExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "Output file, - for stdout")
+
+	packSizesImportCmd.Flags().StringVarP(&importFormat, "format", "f", "",
+		"File format: csv, json or yaml. Defaults to the file extension")
+	packSizesImportCmd.Flags().StringVarP(&importFile, "file", "i", "", "File to import")
+	packSizesImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Only validate the file")
+	_ = packSizesImportCmd.MarkFlagRequired("file")
+}
+
+func exportPackSizes(ctx context.Context, stdout io.Writer) error {
+	format, err := packio.ParseFormat(exportFormat)
+	if err != nil {
+		return err
+	}
+
+	deps := initializeCLIDependencies(ctx, cfg)
+	defer safelyCloseDependencies(ctx, deps)
+	application := NewApplication(ctx, cfg, deps)
+
+	sizes, err := application.Queries.GetPackSizes.Handle(ctx, &query.GetPackSizesQuery{})
+	if err != nil {
+		return err
+	}
+
+	out := stdout
+	if exportOutput != "-" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return packio.Encode(out, format, sizes)
+}
+
+func importPackSizes(ctx context.Context, stdout io.Writer) error {
+	format, err := importFileFormat()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(importFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoded, err := packio.Decode(f, format)
+	if err != nil {
+		return err
+	}
+	if len(decoded.Errors) > 0 {
+		for _, e := range decoded.Errors {
+			fmt.Fprintln(stdout, e.Error())
+		}
+		return fmt.Errorf("%d invalid rows in %s, nothing was imported", len(decoded.Errors), importFile)
+	}
+	packs := decoded.Packs
+
+	deps := initializeCLIDependencies(ctx, cfg)
+	defer safelyCloseDependencies(ctx, deps)
+	application := NewApplication(ctx, cfg, deps)
+
+	ctx = requestmeta.WithMetadata(ctx, requestmeta.Metadata{Actor: cliActor})
+	err = application.Commands.SetPackSizes.Handle(ctx, &command.SetPackSizesCommand{Sizes: packs, DryRun: importDryRun})
+	if err != nil {
+		return err
+	}
+
+	if importDryRun {
+		fmt.Fprintf(stdout, "%d pack sizes are valid, nothing was imported (dry run)\n", len(packs))
+		return nil
+	}
+	fmt.Fprintf(stdout, "Imported %d pack sizes\n", len(packs))
+	return nil
+}
+
+func importFileFormat() (packio.Format, error) {
+	if importFormat != "" {
+		return packio.ParseFormat(importFormat)
+	}
+	format, err := packio.FormatFromFilename(importFile)
+	if err != nil {
+		return "", errors.Join(err, errors.New("use --format to set it explicitly"))
+	}
+	return format, nil
+}
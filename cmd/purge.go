@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rossi1/smart-pack/app"
+	"github.com/rossi1/smart-pack/app/command"
+	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	purgeOlderThanDays int
+	purgeKeepVersions  int
+	purgeArchive       bool
+	purgeDryRun        bool
+)
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "remove retired pack-size generations past the retention policy",
+	Long: "Remove soft-deleted pack-size generations that are older than --older-than-days or\n" +
+		"fall outside the newest --keep-versions. Flags default to the PACK_SIZES_RETENTION_* settings.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		policy := retentionPolicy(cfg)
+		flags := cmd.Flags()
+		if flags.Changed("older-than-days") {
+			policy.MaxAge = days(purgeOlderThanDays)
+		}
+		if flags.Changed("keep-versions") {
+			policy.KeepVersions = purgeKeepVersions
+		}
+		if flags.Changed("archive") {
+			policy.Archive = purgeArchive
+		}
+		checkErr(purgePackSizes(cmd.Context(), cmd.OutOrStdout(), policy))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+
+	purgeCmd.Flags().IntVar(&purgeOlderThanDays, "older-than-days", 0, "Purge generations retired more than this many days ago, 0 to disable")
+	purgeCmd.Flags().IntVar(&purgeKeepVersions, "keep-versions", 0, "Keep only this many of the newest retired generations, 0 to disable")
+	purgeCmd.Flags().BoolVar(&purgeArchive, "archive", false, "Move purged rows to smartpack_archive instead of deleting them")
+	purgeCmd.Flags().BoolVar(&purgeDryRun, "dry-run", false, "Only report what would be purged")
+}
+
+func purgePackSizes(ctx context.Context, stdout io.Writer, policy domain.RetentionPolicy) error {
+	if !policy.Enabled() {
+		return fmt.Errorf("no retention limit set, use --older-than-days or --keep-versions")
+	}
+
+	deps := initializeCLIDependencies(ctx, cfg)
+	defer safelyCloseDependencies(ctx, deps)
+	application := NewApplication(ctx, cfg, deps)
+
+	cmd := &command.PurgePackSizesCommand{Policy: policy, DryRun: purgeDryRun}
+	if err := application.Commands.PurgePackSizes.Handle(ctx, cmd); err != nil {
+		return err
+	}
+	printPurgeReport(stdout, cmd.Report)
+	return nil
+}
+
+func printPurgeReport(out io.Writer, report domain.PurgeReport) {
+	verb := "Purged"
+	switch {
+	case report.DryRun:
+		verb = "Would purge"
+	case report.Archived:
+		verb = "Archived"
+	}
+	fmt.Fprintf(out, "%s %d packs from %d retired generations\n", verb, report.Packs, report.Generations)
+	if report.OldestRetiredAt != nil {
+		fmt.Fprintf(out, "oldest: %s\nnewest: %s\n",
+			report.OldestRetiredAt.Format(time.RFC3339), report.NewestRetiredAt.Format(time.RFC3339))
+	}
+}
+
+func retentionPolicy(cfg *appConfig.AppConfig) domain.RetentionPolicy {
+	return domain.RetentionPolicy{
+		MaxAge:       days(cfg.RetentionDays),
+		KeepVersions: cfg.RetentionVersions,
+		Archive:      cfg.RetentionArchive,
+	}
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// startPurgeJob applies the configured retention policy every
+// PACK_SIZES_PURGE_INTERVAL until ctx is done or the returned stop is called.
+func startPurgeJob(ctx context.Context, cfg *appConfig.AppConfig, application *app.Application) (stop func()) {
+	policy := retentionPolicy(cfg)
+	if cfg.PurgeInterval <= 0 || !policy.Enabled() {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cmd := &command.PurgePackSizesCommand{Policy: policy}
+				if err := application.Commands.PurgePackSizes.Handle(ctx, cmd); err != nil {
+					logrus.WithContext(ctx).WithError(err).Error("Pack sizes purge failed")
+					continue
+				}
+				if cmd.Report.Generations > 0 {
+					logrus.WithContext(ctx).WithFields(logrus.Fields{
+						"generations": cmd.Report.Generations,
+						"packs":       cmd.Report.Packs,
+						"archived":    cmd.Report.Archived,
+					}).Info("Purged retired pack sizes")
+				}
+			}
+		}
+	}()
+	return cancel
+}
@@ -2,11 +2,20 @@ package cmd
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/app/command"
 	appConfig "github.com/rossi1/smart-pack/config"
 	"github.com/rossi1/smart-pack/pkg/config"
+	"github.com/rossi1/smart-pack/pkg/tracing"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -44,14 +53,84 @@ func Execute() {
 }
 
 type Dependencies struct {
-	DB *pgx.Conn
+	DB     *pgxpool.Pool
+	SQLite *sql.DB
+	Memory *adapters.MemoryStore
+
+	// PackSizesFile, when set, serves pack sizes instead of the database.
+	PackSizesFile *adapters.FilePackSizesRepository
+	// PackSizesListener, when set, reports pack-size changes committed by
+	// any replica to the pack-size cache and streams.
+	PackSizesListener *adapters.PostgresListener
+	// OutboxPublisher, when set, delivers outbox events to downstream
+	// services.
+	OutboxPublisher command.EventPublisher
 }
 
 func (d *Dependencies) Close(ctx context.Context) error {
+	var errs []error
+	if d.PackSizesListener != nil {
+		errs = append(errs, d.PackSizesListener.Close())
+	}
+	if d.PackSizesFile != nil {
+		errs = append(errs, d.PackSizesFile.Close())
+	}
+	if closer, ok := d.OutboxPublisher.(io.Closer); ok {
+		errs = append(errs, closer.Close())
+	}
 	if d.DB != nil {
-		return d.DB.Close(ctx)
+		d.DB.Close()
+	}
+	if d.SQLite != nil {
+		errs = append(errs, d.SQLite.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// storageDriver resolves the configured driver, letting a sqlite:// database
+// URL select SQLite without also setting STORAGE_DRIVER.
+func storageDriver(cfg *appConfig.AppConfig) string {
+	switch {
+	case cfg.StorageDriver == appConfig.StorageDriverMemory:
+		return appConfig.StorageDriverMemory
+	case cfg.StorageDriver == appConfig.StorageDriverSQLite, adapters.IsSQLiteURL(cfg.DatabaseURL):
+		return appConfig.StorageDriverSQLite
+	case cfg.StorageDriver == appConfig.StorageDriverPostgres, cfg.StorageDriver == "":
+		return appConfig.StorageDriverPostgres
+	}
+	return cfg.StorageDriver
+}
+
+// migrationPath returns the migrations directory matching the storage
+// driver; the SQLite flavour lives in a sqlite subdirectory. It is empty, and
+// the embedded migrations are used, unless DATABASE_MIGRATION_PATH is set.
+func migrationPath(cfg *appConfig.AppConfig) string {
+	if cfg.DatabaseMigrationPath == "" {
+		return ""
+	}
+	if storageDriver(cfg) == appConfig.StorageDriverSQLite {
+		return filepath.Join(cfg.DatabaseMigrationPath, appConfig.StorageDriverSQLite)
+	}
+	return cfg.DatabaseMigrationPath
+}
+
+// tracingFlushTimeout bounds how long exiting waits for buffered spans.
+const tracingFlushTimeout = 5 * time.Second
+
+// startTracing installs the trace exporter of a long-running command. The
+// returned function flushes the buffered spans.
+func startTracing(ctx context.Context, cfg *appConfig.AppConfig) func() {
+	shutdown, err := tracing.Setup(ctx, cfg)
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).Fatal("Error while configuring tracing")
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logrus.WithContext(ctx).WithError(err).Error("Error while flushing traces")
+		}
 	}
-	return nil
 }
 
 func checkErr(err error) {
@@ -60,21 +139,37 @@ func checkErr(err error) {
 	}
 }
 
-func createPostgresConnection(ctx context.Context, cfg *appConfig.AppConfig) (*pgx.Conn, error) {
-	dbOptions, err := pgx.ParseConfig(cfg.DatabaseURL)
+func createPostgresPool(ctx context.Context, cfg *appConfig.AppConfig) (*pgxpool.Pool, error) {
+	poolOptions, err := pgxpool.ParseConfig(cfg.DatabaseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	db, err := pgx.ConnectConfig(ctx, dbOptions)
+	if cfg.DatabaseMaxConns > 0 {
+		poolOptions.MaxConns = cfg.DatabaseMaxConns
+	}
+	poolOptions.MinConns = cfg.DatabaseMinConns
+	if cfg.DatabaseMaxConnIdleTime > 0 {
+		poolOptions.MaxConnIdleTime = cfg.DatabaseMaxConnIdleTime
+	}
+	if cfg.DatabaseMaxConnLifetime > 0 {
+		poolOptions.MaxConnLifetime = cfg.DatabaseMaxConnLifetime
+	}
+	if cfg.DatabaseHealthCheckPeriod > 0 {
+		poolOptions.HealthCheckPeriod = cfg.DatabaseHealthCheckPeriod
+	}
+
+	// Queries nest under the span of the request or job that runs them.
+	poolOptions.ConnConfig.Tracer = tracing.PgxTracer{}
+
+	db, err := pgxpool.NewWithConfig(ctx, poolOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	// test connection
-	_, err = db.Exec(ctx, "SELECT 1")
-	if err != nil {
-		db.Close(ctx)
+	if err := db.Ping(ctx); err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/rossi1/smart-pack/app"
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/common/requestmeta"
+	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// webhookEventPublisher feeds outbox events to the webhook subscriptions.
+// The event id is derived from the outbox row, so an event the relay hands
+// over twice is still queued once per subscription.
+type webhookEventPublisher struct {
+	enqueue command.EnqueueWebhookEventHandler
+}
+
+func (p webhookEventPublisher) Publish(ctx context.Context, event domain.OutboxEvent) error {
+	return p.enqueue.Handle(ctx, &command.EnqueueWebhookEventCommand{
+		EventID:    "outbox_" + strconv.FormatInt(event.ID, 10),
+		Type:       event.Type,
+		OccurredAt: event.CreatedAt,
+		Data:       json.RawMessage(event.Payload),
+	})
+}
+
+// calculationCompletedNotifier queues a calculation.completed webhook for
+// the subscriptions that want it. A failure is logged and never fails the
+// calculation.
+type calculationCompletedNotifier struct {
+	enqueue command.EnqueueWebhookEventHandler
+}
+
+func (n calculationCompletedNotifier) Notify(ctx context.Context, result *domain.PackSolution) {
+	err := n.enqueue.Handle(ctx, &command.EnqueueWebhookEventCommand{
+		Type:       domain.EventCalculationCompleted,
+		OccurredAt: time.Now(),
+		Data:       domain.NewCalculationCompleted(result, requestmeta.FromContext(ctx).RequestID),
+	})
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).Error("Failed to queue calculation.completed webhook")
+	}
+}
+
+// startWebhookDelivery sends due webhook deliveries every
+// WEBHOOK_DELIVERY_INTERVAL until ctx is done or the returned stop is called.
+func startWebhookDelivery(ctx context.Context, cfg *appConfig.AppConfig, application *app.Application) (stop func()) {
+	deliver := application.Commands.DeliverWebhooks
+	if deliver == nil || cfg.WebhookDeliveryInterval <= 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ticker := time.NewTicker(cfg.WebhookDeliveryInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deliverWebhooks(ctx, deliver, cfg.WebhookBatchSize, cfg.WebhookMaxAttempts)
+			}
+		}
+	}()
+	return cancel
+}
+
+// deliverWebhooks keeps claiming batches until one comes back short or empty,
+// like relayOutbox.
+func deliverWebhooks(ctx context.Context, deliver command.DeliverWebhooksHandler, batchSize, maxAttempts int) {
+	for ctx.Err() == nil {
+		cmd := &command.DeliverWebhooksCommand{Limit: batchSize, MaxAttempts: maxAttempts}
+		if err := deliver.Handle(ctx, cmd); err != nil {
+			logrus.WithContext(ctx).WithError(err).Error("Webhook delivery failed")
+			return
+		}
+		claimed := cmd.Succeeded + cmd.Failed
+		if claimed == 0 || claimed < batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+
+	"github.com/rossi1/smart-pack/adapters"
+	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/pkg/broker"
+	"github.com/rossi1/smart-pack/ports/messaging"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "calculate pack plans for order-created messages",
+	Long: "Consume order-created messages from BROKER_URL, calculate their pack plan and publish it to the\n" +
+		"message's reply subject or WORKER_RESULT_SUBJECT. Invalid messages go to WORKER_DEAD_LETTER_SUBJECT,\n" +
+		"and so do orders that still fail after WORKER_RETRY_ATTEMPTS attempts.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		startWorker(cmd.Context(), cfg)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+}
+
+func startWorker(ctx context.Context, cfg *appConfig.AppConfig) {
+	stopTracing := startTracing(ctx, cfg)
+	defer stopTracing()
+
+	deps := initializeDependencies(ctx, cfg)
+	defer safelyCloseDependencies(ctx, deps)
+
+	if err := prepareSchema(ctx, cfg, deps); err != nil {
+		logrus.WithContext(ctx).WithError(err).Fatal("Database schema is not ready")
+	}
+
+	application := NewApplication(ctx, cfg, deps)
+
+	b, err := NewBroker(cfg)
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).Fatal("Error while connecting to the message broker")
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	consumer := messaging.NewOrderConsumer(application, b, WorkerSubjects(cfg), WorkerRetry(cfg))
+	if err := consumer.Start(ctx, cfg.WorkerConcurrency); err != nil {
+		logrus.WithContext(ctx).WithError(err).Fatal("Error while subscribing to orders")
+	}
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"subject":     cfg.WorkerOrderSubject,
+		"queue":       cfg.WorkerQueueGroup,
+		"concurrency": cfg.WorkerConcurrency,
+	}).Info("Worker started")
+
+	<-ctx.Done()
+	logrus.Info("Worker is shutting down...")
+	if err := b.Close(); err != nil {
+		logrus.WithError(err).Error("Error closing the message broker")
+	}
+	logrus.Info("Worker stopped")
+}
+
+// NewBroker connects to BROKER_URL.
+func NewBroker(cfg *appConfig.AppConfig) (broker.Broker, error) {
+	if cfg.BrokerURL == appConfig.BrokerMemory {
+		return broker.NewMemoryBroker(), nil
+	}
+	return adapters.NewNATSBroker(cfg.BrokerURL)
+}
+
+func WorkerSubjects(cfg *appConfig.AppConfig) messaging.Subjects {
+	return messaging.Subjects{
+		Orders:     cfg.WorkerOrderSubject,
+		Queue:      cfg.WorkerQueueGroup,
+		Results:    cfg.WorkerResultSubject,
+		DeadLetter: cfg.WorkerDeadLetterSubject,
+	}
+}
+
+func WorkerRetry(cfg *appConfig.AppConfig) messaging.Retry {
+	return messaging.Retry{
+		Attempts: cfg.WorkerRetryAttempts,
+		Backoff:  cfg.WorkerRetryBackoff,
+	}
+}
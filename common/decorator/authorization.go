@@ -0,0 +1,61 @@
+package decorator
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/common/requestmeta"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// ApplyAuthorizedCommandDecorators is ApplyCommandDecorators for commands
+// that callers need perm to run.
+func ApplyAuthorizedCommandDecorators[H any](handler CommandHandler[H], perm domain.Permission) CommandHandler[H] {
+	return ApplyCommandDecorators[H](commandAuthorizationDecorator[H]{
+		base: handler,
+		perm: perm,
+	})
+}
+
+// ApplyAuthorizedQueryDecorators is ApplyQueryDecorators for queries that
+// callers need perm to run.
+func ApplyAuthorizedQueryDecorators[H any, R any](handler QueryHandler[H, R], perm domain.Permission) QueryHandler[H, R] {
+	return ApplyQueryDecorators[H, R](queryAuthorizationDecorator[H, R]{
+		base: handler,
+		perm: perm,
+	})
+}
+
+// Authorize returns domain.ErrForbidden unless the principal in ctx has
+// perm. Without a principal the caller is trusted: authentication is
+// disabled, or the call comes from the CLI, a background job or the worker.
+func Authorize(ctx context.Context, perm domain.Permission) error {
+	p := requestmeta.FromContext(ctx).Principal
+	if p == nil || p.Can(perm) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+type commandAuthorizationDecorator[C any] struct {
+	base CommandHandler[C]
+	perm domain.Permission
+}
+
+func (d commandAuthorizationDecorator[C]) Handle(ctx context.Context, cmd C) error {
+	if err := Authorize(ctx, d.perm); err != nil {
+		return err
+	}
+	return d.base.Handle(ctx, cmd)
+}
+
+type queryAuthorizationDecorator[C any, R any] struct {
+	base QueryHandler[C, R]
+	perm domain.Permission
+}
+
+func (d queryAuthorizationDecorator[C, R]) Handle(ctx context.Context, q C) (result R, err error) {
+	if err := Authorize(ctx, d.perm); err != nil {
+		return result, err
+	}
+	return d.base.Handle(ctx, q)
+}
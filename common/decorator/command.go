@@ -8,7 +8,11 @@ import (
 
 func ApplyCommandDecorators[H any](handler CommandHandler[H]) CommandHandler[H] {
 	return commandLoggingDecorator[H]{
-		base: handler,
+		base: commandTracingDecorator[H]{
+			base: commandMetricsDecorator[H]{
+				base: handler,
+			},
+		},
 	}
 }
 
@@ -0,0 +1,41 @@
+package decorator
+
+import (
+	"context"
+	"time"
+
+	"github.com/rossi1/smart-pack/pkg/metrics"
+)
+
+// The metrics decorators count a panic as a failure without recovering it,
+// which the logging decorator does with the original stack.
+
+type commandMetricsDecorator[C any] struct {
+	base CommandHandler[C]
+}
+
+func (d commandMetricsDecorator[C]) Handle(ctx context.Context, cmd C) error {
+	start, failed := time.Now(), true
+	defer func() {
+		metrics.ObserveHandler("command", generateActionName(cmd), time.Since(start), failed)
+	}()
+
+	err := d.base.Handle(ctx, cmd)
+	failed = err != nil
+	return err
+}
+
+type queryMetricsDecorator[C any, R any] struct {
+	base QueryHandler[C, R]
+}
+
+func (d queryMetricsDecorator[C, R]) Handle(ctx context.Context, q C) (R, error) {
+	start, failed := time.Now(), true
+	defer func() {
+		metrics.ObserveHandler("query", generateActionName(q), time.Since(start), failed)
+	}()
+
+	result, err := d.base.Handle(ctx, q)
+	failed = err != nil
+	return result, err
+}
@@ -6,7 +6,11 @@ import (
 
 func ApplyQueryDecorators[H any, R any](handler QueryHandler[H, R]) QueryHandler[H, R] {
 	return queryLoggingDecorator[H, R]{
-		base: handler,
+		base: queryTracingDecorator[H, R]{
+			base: queryMetricsDecorator[H, R]{
+				base: handler,
+			},
+		},
 	}
 }
 
@@ -0,0 +1,52 @@
+package decorator
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The tracing decorators start a span named after the command or query, so
+// that the SQL it runs nests under it.
+
+var handlerKindKey = attribute.Key("smartpack.handler.kind")
+
+type commandTracingDecorator[C any] struct {
+	base CommandHandler[C]
+}
+
+func (d commandTracingDecorator[C]) Handle(ctx context.Context, cmd C) error {
+	ctx, span := startHandlerSpan(ctx, "command", generateActionName(cmd))
+	defer span.End()
+
+	err := d.base.Handle(ctx, cmd)
+	endHandlerSpan(span, err)
+	return err
+}
+
+type queryTracingDecorator[C any, R any] struct {
+	base QueryHandler[C, R]
+}
+
+func (d queryTracingDecorator[C, R]) Handle(ctx context.Context, q C) (R, error) {
+	ctx, span := startHandlerSpan(ctx, "query", generateActionName(q))
+	defer span.End()
+
+	result, err := d.base.Handle(ctx, q)
+	endHandlerSpan(span, err)
+	return result, err
+}
+
+func startHandlerSpan(ctx context.Context, kind, name string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name, trace.WithAttributes(handlerKindKey.String(kind)))
+}
+
+func endHandlerSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,41 @@
+package requestmeta
+
+import (
+	"context"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+const AnonymousActor = "anonymous"
+
+type contextKey struct{}
+
+// Metadata describes who triggered the current request and where it came from.
+type Metadata struct {
+	// Actor is the authenticated caller, or anonymous.
+	Actor string
+	// ClaimedActor is the caller named by the client, which nothing checks.
+	ClaimedActor string
+	SourceIP     string
+	RequestID    string
+	// Principal is the authenticated caller. It is nil when authentication
+	// is disabled and outside HTTP requests.
+	Principal *domain.Principal
+}
+
+func WithMetadata(ctx context.Context, m Metadata) context.Context {
+	return context.WithValue(ctx, contextKey{}, m)
+}
+
+// FromContext returns the request metadata stored in ctx. Callers outside an
+// HTTP request (CLI, background jobs) get an anonymous actor.
+func FromContext(ctx context.Context) Metadata {
+	m, ok := ctx.Value(contextKey{}).(Metadata)
+	if !ok {
+		return Metadata{Actor: AnonymousActor}
+	}
+	if m.Actor == "" {
+		m.Actor = AnonymousActor
+	}
+	return m
+}
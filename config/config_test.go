@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppConfigValidate(t *testing.T) {
+	valid := func() *AppConfig {
+		return &AppConfig{OutboxBatchSize: 100, WebhookBatchSize: 100, WorkerRetryAttempts: 5}
+	}
+
+	testCases := []struct {
+		name      string
+		modify    func(c *AppConfig)
+		expectErr string
+	}{
+		{
+			name:   "defaults",
+			modify: func(c *AppConfig) {},
+		},
+		{
+			name:      "zero outbox batch size",
+			modify:    func(c *AppConfig) { c.OutboxBatchSize = 0 },
+			expectErr: "OUTBOX_BATCH_SIZE must be positive",
+		},
+		{
+			name:      "negative webhook batch size",
+			modify:    func(c *AppConfig) { c.WebhookBatchSize = -1 },
+			expectErr: "WEBHOOK_BATCH_SIZE must be positive",
+		},
+		{
+			name:      "zero worker retry attempts",
+			modify:    func(c *AppConfig) { c.WorkerRetryAttempts = 0 },
+			expectErr: "WORKER_RETRY_ATTEMPTS must be positive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid()
+			tc.modify(c)
+
+			err := c.Validate()
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
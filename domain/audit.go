@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+const (
+	AuditActionSetPackSizes = "pack_sizes.set"
+)
+
+type AuditEntry struct {
+	ID     int64
+	Action string
+	Actor  string
+	// ClaimedActor is the caller the client named itself. It is not
+	// verified and must not be trusted for attribution.
+	ClaimedActor string
+	SourceIP     string
+	RequestID    string
+	Before       []SmartPack
+	After        []SmartPack
+	CreatedAt    time.Time
+}
+
+type AuditFilter struct {
+	Action    string
+	Actor     string
+	RequestID string
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "slices"
+
+// Role groups the permissions granted to an API caller. Each role includes
+// the permissions of the ones before it.
+type Role string
+
+const (
+	RoleViewer     Role = "viewer"
+	RoleCalculator Role = "calculator"
+	RoleAdmin      Role = "admin"
+)
+
+// Permission is checked by the application before running a command or
+// query on behalf of a caller.
+type Permission string
+
+const (
+	PermissionReadPackSizes  Permission = "pack_sizes:read"
+	PermissionCalculatePacks Permission = "packs:calculate"
+	PermissionWritePackSizes Permission = "pack_sizes:write"
+	PermissionReadAudit      Permission = "audit:read"
+	PermissionManageWebhooks Permission = "webhooks:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {
+		PermissionReadPackSizes,
+	},
+	RoleCalculator: {
+		PermissionReadPackSizes,
+		PermissionCalculatePacks,
+	},
+	RoleAdmin: {
+		PermissionReadPackSizes,
+		PermissionCalculatePacks,
+		PermissionWritePackSizes,
+		PermissionReadAudit,
+		PermissionManageWebhooks,
+	},
+}
+
+var (
+	ErrUnauthenticated = NewCustomError(
+		ErrorUnauthenticatedLabel,
+		"missing or invalid credentials",
+		unauthorizedStatus,
+	)
+	ErrForbidden = NewCustomError(
+		ErrorForbiddenLabel,
+		"not allowed for the caller's roles",
+		forbiddenStatus,
+	)
+)
+
+// ParseRole returns the role named s.
+func ParseRole(s string) (Role, bool) {
+	r := Role(s)
+	_, ok := rolePermissions[r]
+	return r, ok
+}
+
+// Principal is an authenticated API caller.
+type Principal struct {
+	// Subject names the caller in the audit log.
+	Subject string
+	Roles   []Role
+}
+
+// Can reports whether any of the principal's roles grants p.
+func (p Principal) Can(perm Permission) bool {
+	for _, r := range p.Roles {
+		if slices.Contains(rolePermissions[r], perm) {
+			return true
+		}
+	}
+	return false
+}
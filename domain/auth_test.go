@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipalCan(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Roles      []Role
+		Permission Permission
+		Allowed    bool
+	}{
+		{Name: "viewer reads pack sizes", Roles: []Role{RoleViewer}, Permission: PermissionReadPackSizes, Allowed: true},
+		{Name: "viewer cannot calculate", Roles: []Role{RoleViewer}, Permission: PermissionCalculatePacks},
+		{Name: "calculator calculates", Roles: []Role{RoleCalculator}, Permission: PermissionCalculatePacks, Allowed: true},
+		{Name: "calculator reads pack sizes", Roles: []Role{RoleCalculator}, Permission: PermissionReadPackSizes, Allowed: true},
+		{Name: "calculator cannot write pack sizes", Roles: []Role{RoleCalculator}, Permission: PermissionWritePackSizes},
+		{Name: "admin writes pack sizes", Roles: []Role{RoleAdmin}, Permission: PermissionWritePackSizes, Allowed: true},
+		{Name: "admin manages webhooks", Roles: []Role{RoleAdmin}, Permission: PermissionManageWebhooks, Allowed: true},
+		{Name: "any role grants", Roles: []Role{RoleViewer, RoleAdmin}, Permission: PermissionReadAudit, Allowed: true},
+		{Name: "unknown role", Roles: []Role{"owner"}, Permission: PermissionReadPackSizes},
+		{Name: "no roles", Permission: PermissionReadPackSizes},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			p := Principal{Subject: "test", Roles: tc.Roles}
+			require.Equal(t, tc.Allowed, p.Can(tc.Permission))
+		})
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	r, ok := ParseRole("calculator")
+	require.True(t, ok)
+	require.Equal(t, RoleCalculator, r)
+
+	_, ok = ParseRole("owner")
+	require.False(t, ok)
+}
@@ -1,8 +1,34 @@
 package domain
 
 const (
-	ErrorInternalServerErrorLabel    = "error_internal_server_error"
-	ErrorUnprocessableEntityLabel    = "error_unprocessable_entity"
-	ErrorBadRequestLabel             = "error_bad_request"
-	ErrorInvalidRequestBodyParameter = "error_invalid_request_body_parameter"
+	ErrorInternalServerErrorLabel      = "error_internal_server_error"
+	ErrorUnprocessableEntityLabel      = "error_unprocessable_entity"
+	ErrorBadRequestLabel               = "error_bad_request"
+	ErrorUnauthenticatedLabel          = "error_unauthenticated"
+	ErrorForbiddenLabel                = "error_forbidden"
+	ErrorNotAcceptableLabel            = "error_not_acceptable"
+	ErrorConflictLabel                 = "error_conflict"
+	ErrorRateLimitedLabel              = "error_rate_limit_exceeded"
+	ErrorInvalidRequestBodyParameter   = "error_invalid_request_body_parameter"
+	ErrorInvalidRequestParameterLabel  = "error_invalid_request_parameter"
+	ErrorInvalidPackSizeLabel          = "error_invalid_pack_size"
+	ErrorDuplicatePackSizeLabel        = "error_duplicate_pack_size"
+	ErrorInvalidPackLabelLabel         = "error_invalid_pack_label"
+	ErrorInvalidPackSKULabel           = "error_invalid_pack_sku"
+	ErrorInvalidPackGTINLabel          = "error_invalid_pack_gtin"
+	ErrorPackSizesReadOnlyLabel        = "error_pack_sizes_read_only"
+	ErrorInvalidItemsOrderedLabel      = "error_invalid_items_ordered"
+	ErrorItemsOrderedLimitLabel        = "error_items_ordered_limit_exceeded"
+	ErrorPackSizeLimitLabel            = "error_pack_size_limit_exceeded"
+	ErrorPackSizesLimitLabel           = "error_pack_sizes_limit_exceeded"
+	ErrorRequestBodyTooLargeLabel      = "error_request_body_too_large"
+	ErrorNoPackSizesLabel              = "error_no_pack_sizes_configured"
+	ErrorOrderInfeasibleLabel          = "error_order_infeasible"
+	ErrorInvalidOrderIDLabel           = "error_invalid_order_id"
+	ErrorInvalidWebhookURLLabel        = "error_invalid_webhook_url"
+	ErrorInvalidWebhookEventLabel      = "error_invalid_webhook_event"
+	ErrorInvalidWebhookSecretLabel     = "error_invalid_webhook_secret"
+	ErrorWebhookNotFoundLabel          = "error_webhook_not_found"
+	ErrorWebhookDeliveryNotFoundLabel  = "error_webhook_delivery_not_found"
+	ErrorWebhookDeliveryNotFailedLabel = "error_webhook_delivery_not_failed"
 )
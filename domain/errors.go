@@ -1,25 +1,43 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 const (
 	BadRequestStatus          = 400
+	unauthorizedStatus        = 401
+	forbiddenStatus           = 403
 	notFoundStatus            = 404
 	conflictStatus            = 409
+	payloadTooLargeStatus     = 413
 	UnprocessableEntity       = 422
+	tooManyRequestsStatus     = 429
 	InternalServerErrorStatus = 500
 )
 
+// ErrConflict is returned when a write lost a race with a concurrent one.
+// Retrying it is safe.
+var ErrConflict = NewCustomError(ErrorConflictLabel, "conflicting concurrent change, retry", conflictStatus)
+
 type CustomError struct {
-	l string
-	e string
-	s int
+	l     string
+	e     string
+	f     string
+	s     int
+	limit int64
 }
 
 func NewCustomError(l, e string, s int) *CustomError {
 	return &CustomError{l: l, e: e, s: s}
 }
 
+// NewFieldError creates a CustomError pointing at the offending input field.
+func NewFieldError(l, f string, s int) *CustomError {
+	return &CustomError{l: l, e: f + ": " + l, f: f, s: s}
+}
+
 func (x CustomError) Error() string {
 	return x.e
 }
@@ -32,12 +50,69 @@ func (x *CustomError) Label() string {
 	return x.l
 }
 
+func (x *CustomError) Field() string {
+	return x.f
+}
+
+// Limit is the configured limit the input exceeded, or zero.
+func (x *CustomError) Limit() int64 {
+	return x.limit
+}
+
+// Is matches any CustomError with the same label, so errors.Is works for
+// errors that carry a field or limit, e.g. against ErrItemsOrderedLimit.
+func (x *CustomError) Is(target error) bool {
+	t, ok := target.(*CustomError)
+	return ok && t.l == x.l
+}
+
 func IsHTTPCustomError(err error) (*CustomError, bool) {
 	var cerr *CustomError
 	ok := errors.As(err, &cerr)
 	return cerr, ok
 }
 
+// FieldErrors reports every invalid field of one input at once. errors.As and
+// IsHTTPCustomError find the first of them.
+type FieldErrors []*CustomError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// orNil keeps an empty list from becoming a non-nil error.
+func (e FieldErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// CustomErrors returns every CustomError in err: all of a FieldErrors, or
+// the first one found otherwise.
+func CustomErrors(err error) []*CustomError {
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		return fieldErrs
+	}
+	if v, ok := IsHTTPCustomError(err); ok {
+		return []*CustomError{v}
+	}
+	return nil
+}
+
 type ErrorReporter interface {
 	ReportError(err error)
 }
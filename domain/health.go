@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// DatabaseStats is a snapshot of the database connection pool.
+type DatabaseStats struct {
+	MaxConns             int32
+	TotalConns           int32
+	IdleConns            int32
+	AcquiredConns        int32
+	ConstructingConns    int32
+	AcquireCount         int64
+	EmptyAcquireCount    int64
+	CanceledAcquireCount int64
+	AcquireDuration      time.Duration
+}
@@ -0,0 +1,64 @@
+package domain
+
+import "fmt"
+
+// InputLimits bound the work a single request can cause. Zero disables a
+// limit.
+type InputLimits struct {
+	// MaxItemsOrdered bounds a calculation, whose memory grows with the
+	// order plus the largest pack size.
+	MaxItemsOrdered int
+	MaxPackSize     int
+	// MaxPackSizes bounds the number of packs in a set.
+	MaxPackSizes int
+}
+
+// DefaultInputLimits keep a calculation within a few tens of megabytes.
+var DefaultInputLimits = InputLimits{
+	MaxItemsOrdered: 1_000_000,
+	MaxPackSize:     1_000_000,
+	MaxPackSizes:    100,
+}
+
+// NewLimitError reports an input above a configured limit. The limit is part
+// of the message and available through Limit.
+func NewLimitError(l, f string, limit int64, s int) *CustomError {
+	return &CustomError{l: l, e: fmt.Sprintf("%s: exceeds the limit of %d", f, limit), f: f, s: s, limit: limit}
+}
+
+// NewRequestBodyTooLargeError is returned for a request body above limit
+// bytes.
+func NewRequestBodyTooLargeError(limit int64) *CustomError {
+	return &CustomError{
+		l:     ErrorRequestBodyTooLargeLabel,
+		e:     fmt.Sprintf("request body exceeds the limit of %d bytes", limit),
+		s:     payloadTooLargeStatus,
+		limit: limit,
+	}
+}
+
+// ValidateItemsOrdered checks the size of an order before it is calculated.
+func (l InputLimits) ValidateItemsOrdered(itemsOrdered int) error {
+	if itemsOrdered <= 0 {
+		return ErrInvalidItemsOrdered
+	}
+	if l.MaxItemsOrdered > 0 && itemsOrdered > l.MaxItemsOrdered {
+		return NewLimitError(ErrorItemsOrderedLimitLabel, "items_ordered", int64(l.MaxItemsOrdered), UnprocessableEntity)
+	}
+	return nil
+}
+
+// ValidateEnabledPackSizes checks the pack sizes a calculation would use,
+// which may have been stored before the limits were lowered or loaded from
+// a file.
+func (l InputLimits) ValidateEnabledPackSizes(sizes []int) error {
+	if l.MaxPackSizes > 0 && len(sizes) > l.MaxPackSizes {
+		return NewLimitError(ErrorPackSizesLimitLabel, "packs", int64(l.MaxPackSizes), UnprocessableEntity)
+	}
+	for _, size := range sizes {
+		if l.MaxPackSize > 0 && size > l.MaxPackSize {
+			return NewLimitError(ErrorPackSizeLimitLabel, "size", int64(l.MaxPackSize), UnprocessableEntity)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventPackSizesChanged = "pack_sizes.changed"
+)
+
+// OutboxEvent is an event recorded in the same transaction as the change it
+// describes and delivered to downstream services by the outbox relay.
+// Delivery is at least once: consumers should deduplicate on ID.
+type OutboxEvent struct {
+	ID            int64
+	Type          string
+	Payload       []byte
+	CreatedAt     time.Time
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	PublishedAt   *time.Time
+}
+
+// PackSizesChanged is the payload of an EventPackSizesChanged event.
+type PackSizesChanged struct {
+	Before     []EventPack `json:"before"`
+	After      []EventPack `json:"after"`
+	Actor      string      `json:"actor,omitempty"`
+	RequestID  string      `json:"request_id,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+type EventPack struct {
+	Size    int    `json:"size"`
+	Label   string `json:"label,omitempty"`
+	SKU     string `json:"sku,omitempty"`
+	GTIN    string `json:"gtin,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+func NewPackSizesChangedEvent(change PackSizesChanged) (OutboxEvent, error) {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	return OutboxEvent{
+		Type:          EventPackSizesChanged,
+		Payload:       payload,
+		CreatedAt:     change.OccurredAt,
+		NextAttemptAt: change.OccurredAt,
+	}, nil
+}
+
+func NewEventPacks(packs []SmartPack) []EventPack {
+	events := make([]EventPack, 0, len(packs))
+	for _, p := range packs {
+		events = append(events, EventPack{
+			Size:    p.Size,
+			Label:   p.Label,
+			SKU:     p.SKU,
+			GTIN:    p.GTIN,
+			Enabled: p.Enabled,
+		})
+	}
+	return events
+}
+
+// OutboxRetryDelay is how long the relay waits before retrying an event that
+// failed attempts times: one second, doubling up to five minutes.
+func OutboxRetryDelay(attempts int) time.Duration {
+	const maxDelay = 5 * time.Minute
+	delay := time.Second
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPackSizesChangedEvent(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	event, err := NewPackSizesChangedEvent(PackSizesChanged{
+		Before:     NewEventPacks([]SmartPack{{Size: 250, Enabled: true}}),
+		After:      NewEventPacks([]SmartPack{{Size: 500, SKU: "BOX-500", Enabled: false}}),
+		Actor:      "alice",
+		OccurredAt: now,
+	})
+	require.NoError(t, err)
+	require.Equal(t, EventPackSizesChanged, event.Type)
+	require.Equal(t, now, event.NextAttemptAt)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	require.Equal(t, "alice", payload["actor"])
+	require.Equal(t, []any{map[string]any{"size": 500.0, "sku": "BOX-500", "enabled": false}}, payload["after"])
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	require.Equal(t, time.Second, OutboxRetryDelay(1))
+	require.Equal(t, 2*time.Second, OutboxRetryDelay(2))
+	require.Equal(t, 16*time.Second, OutboxRetryDelay(5))
+	require.Equal(t, 5*time.Minute, OutboxRetryDelay(20))
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRateLimited is returned when a client used up its rate limit.
+var ErrRateLimited = NewCustomError(
+	ErrorRateLimitedLabel,
+	"rate limit exceeded, retry later",
+	tooManyRequestsStatus,
+)
+
+// RateLimit allows Limit requests per Period. Unused requests carry over, so
+// a client that was idle may send up to Limit at once.
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRateLimit reads a limit written as <requests>/<period>, e.g. 20/1s or
+// 10000/24h.
+func ParseRateLimit(s string) (RateLimit, error) {
+	count, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("rate limit %q: want <requests>/<period>", s)
+	}
+	limit, err := strconv.Atoi(count)
+	if err != nil || limit <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q: requests must be a positive number", s)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q: period must be a positive duration", s)
+	}
+	return RateLimit{Limit: limit, Period: d}, nil
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Limit, l.Period)
+}
+
+// perToken is how long the bucket takes to regain one request.
+func (l RateLimit) perToken() time.Duration {
+	return l.Period / time.Duration(l.Limit)
+}
+
+// RateLimitBucket is a token bucket holding the requests a client has left.
+type RateLimitBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewRateLimitBucket returns a full bucket.
+func NewRateLimitBucket(l RateLimit, now time.Time) RateLimitBucket {
+	return RateLimitBucket{Tokens: float64(l.Limit), UpdatedAt: now}
+}
+
+// RateLimitDecision tells whether a request is allowed and what the client
+// has left, for the RateLimit response headers.
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     RateLimit
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed. It is zero
+	// when Allowed.
+	RetryAfter time.Duration
+}
+
+// Take refills the bucket for the time since it was last used and takes one
+// request from it if there is one left.
+func (b RateLimitBucket) Take(l RateLimit, now time.Time) (RateLimitBucket, RateLimitDecision) {
+	elapsed := max(now.Sub(b.UpdatedAt), 0)
+	tokens := math.Min(float64(l.Limit), b.Tokens+elapsed.Seconds()/l.perToken().Seconds())
+
+	d := RateLimitDecision{Limit: l}
+	if tokens >= 1 {
+		tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = tokensDuration(l, 1-tokens)
+	}
+
+	b = RateLimitBucket{Tokens: tokens, UpdatedAt: now}
+	d.Remaining = int(tokens)
+	d.Reset = tokensDuration(l, float64(l.Limit)-tokens)
+	return b, d
+}
+
+// FullAt is when the bucket is full again, after which it can be dropped.
+func (b RateLimitBucket) FullAt(l RateLimit) time.Time {
+	return b.UpdatedAt.Add(tokensDuration(l, float64(l.Limit)-b.Tokens))
+}
+
+func tokensDuration(l RateLimit, tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(l.perToken())))
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	l, err := ParseRateLimit(" 20/1s ")
+	require.NoError(t, err)
+	require.Equal(t, RateLimit{Limit: 20, Period: time.Second}, l)
+	require.Equal(t, "20/1s", l.String())
+
+	for _, s := range []string{"", "20", "0/1s", "-1/1s", "x/1s", "20/0s", "20/day"} {
+		_, err := ParseRateLimit(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestRateLimitBucketTake(t *testing.T) {
+	limit := RateLimit{Limit: 2, Period: time.Second}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewRateLimitBucket(limit, now)
+
+	b, d := b.Take(limit, now)
+	require.Equal(t, RateLimitDecision{Allowed: true, Limit: limit, Remaining: 1, Reset: 500 * time.Millisecond}, d)
+
+	b, d = b.Take(limit, now)
+	require.Equal(t, RateLimitDecision{Allowed: true, Limit: limit, Remaining: 0, Reset: time.Second}, d)
+
+	b, d = b.Take(limit, now.Add(100*time.Millisecond))
+	require.False(t, d.Allowed)
+	require.Equal(t, 0, d.Remaining)
+	require.Equal(t, 400*time.Millisecond, d.RetryAfter)
+	require.Equal(t, 900*time.Millisecond, d.Reset)
+
+	// A refused request costs nothing: the token is back on time.
+	b, d = b.Take(limit, now.Add(500*time.Millisecond))
+	require.True(t, d.Allowed)
+	require.Equal(t, now.Add(1500*time.Millisecond), b.FullAt(limit))
+
+	// Idle time refills the bucket up to the limit, not beyond.
+	_, d = b.Take(limit, now.Add(time.Hour))
+	require.True(t, d.Allowed)
+	require.Equal(t, 1, d.Remaining)
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// PackSizeGeneration is a pack-size set retired by SetPackSizes. All packs of
+// a generation share the time they were soft-deleted.
+type PackSizeGeneration struct {
+	RetiredAt time.Time
+	Packs     int
+}
+
+// RetentionPolicy limits how many retired generations are kept. Each limit
+// applies on its own: a generation is purged once it is older than MaxAge or
+// falls outside the newest KeepVersions. Zero disables a limit.
+type RetentionPolicy struct {
+	MaxAge       time.Duration
+	KeepVersions int
+	// Archive moves purged rows to an archive table instead of deleting them.
+	Archive bool
+}
+
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.KeepVersions > 0
+}
+
+// Expired returns the generations the policy purges at now. generations must
+// be ordered newest first.
+func (p RetentionPolicy) Expired(generations []PackSizeGeneration, now time.Time) []PackSizeGeneration {
+	var expired []PackSizeGeneration
+	for i, g := range generations {
+		tooMany := p.KeepVersions > 0 && i >= p.KeepVersions
+		tooOld := p.MaxAge > 0 && now.Sub(g.RetiredAt) > p.MaxAge
+		if tooMany || tooOld {
+			expired = append(expired, g)
+		}
+	}
+	return expired
+}
+
+// PurgeReport describes what a purge removed, or would remove on a dry run.
+type PurgeReport struct {
+	Generations     int
+	Packs           int
+	Archived        bool
+	DryRun          bool
+	OldestRetiredAt *time.Time
+	NewestRetiredAt *time.Time
+}
+
+func NewPurgeReport(expired []PackSizeGeneration, archive, dryRun bool) PurgeReport {
+	report := PurgeReport{
+		Generations: len(expired),
+		Archived:    archive,
+		DryRun:      dryRun,
+	}
+	for i := range expired {
+		g := expired[i]
+		report.Packs += g.Packs
+		if report.OldestRetiredAt == nil || g.RetiredAt.Before(*report.OldestRetiredAt) {
+			report.OldestRetiredAt = &g.RetiredAt
+		}
+		if report.NewestRetiredAt == nil || g.RetiredAt.After(*report.NewestRetiredAt) {
+			report.NewestRetiredAt = &g.RetiredAt
+		}
+	}
+	return report
+}
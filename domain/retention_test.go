@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicyExpired(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	generations := []PackSizeGeneration{
+		{RetiredAt: now.Add(-1 * day), Packs: 3},
+		{RetiredAt: now.Add(-5 * day), Packs: 2},
+		{RetiredAt: now.Add(-10 * day), Packs: 5},
+		{RetiredAt: now.Add(-40 * day), Packs: 1},
+	}
+
+	testCases := []struct {
+		Name     string
+		Policy   RetentionPolicy
+		Expected []PackSizeGeneration
+	}{
+		{
+			Name:   "disabled",
+			Policy: RetentionPolicy{},
+		},
+		{
+			Name:     "max age",
+			Policy:   RetentionPolicy{MaxAge: 7 * day},
+			Expected: generations[2:],
+		},
+		{
+			Name:     "keep versions",
+			Policy:   RetentionPolicy{KeepVersions: 3},
+			Expected: generations[3:],
+		},
+		{
+			Name:     "either limit",
+			Policy:   RetentionPolicy{MaxAge: 30 * day, KeepVersions: 1},
+			Expected: generations[1:],
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, tc.Policy.Expired(generations, now))
+		})
+	}
+}
+
+func TestNewPurgeReport(t *testing.T) {
+	older := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	report := NewPurgeReport([]PackSizeGeneration{
+		{RetiredAt: newer, Packs: 2},
+		{RetiredAt: older, Packs: 3},
+	}, true, false)
+
+	require.Equal(t, 2, report.Generations)
+	require.Equal(t, 5, report.Packs)
+	require.True(t, report.Archived)
+	require.Equal(t, older, *report.OldestRetiredAt)
+	require.Equal(t, newer, *report.NewestRetiredAt)
+}
@@ -1,12 +1,34 @@
 package domain
 
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	maxPackLabelLength = 100
+	maxPackSKULength   = 64
+)
+
+var (
+	skuPattern  = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	gtinLengths = map[int]bool{8: true, 12: true, 13: true, 14: true}
+)
+
 type SmartPack struct {
-	Size int
+	Size    int
+	Label   string
+	SKU     string
+	GTIN    string
+	Enabled bool
 }
 
 type PackDetail struct {
 	Size     int
 	Quantity int
+	Label    string
+	SKU      string
+	GTIN     string
 }
 
 type PackSolution struct {
@@ -16,3 +38,134 @@ type PackSolution struct {
 	Packs        map[int]int // size -> quantity
 	PackDetails  []PackDetail
 }
+
+// VersionedPackSizes is the active set with its version. Every change
+// produces a higher version, so a client can tell whether it missed one.
+type VersionedPackSizes struct {
+	Version int64
+	Packs   []SmartPack
+}
+
+// ErrPackSizesReadOnly is returned when pack sizes are managed outside the
+// API, e.g. declared in a file, and cannot be changed through it.
+var ErrPackSizesReadOnly = NewCustomError(
+	ErrorPackSizesReadOnlyLabel,
+	"pack sizes are read-only: they are managed outside the API",
+	conflictStatus,
+)
+
+var (
+	// ErrInvalidItemsOrdered is returned for an order of zero or fewer items.
+	ErrInvalidItemsOrdered = NewFieldError(ErrorInvalidItemsOrderedLabel, "items_ordered", BadRequestStatus)
+	// ErrItemsOrderedLimit, ErrPackSizeLimit and ErrPackSizesLimit match, with
+	// errors.Is, the errors for inputs above the InputLimits.
+	ErrItemsOrderedLimit = NewFieldError(ErrorItemsOrderedLimitLabel, "items_ordered", UnprocessableEntity)
+	ErrPackSizeLimit     = NewFieldError(ErrorPackSizeLimitLabel, "size", UnprocessableEntity)
+	ErrPackSizesLimit    = NewFieldError(ErrorPackSizesLimitLabel, "packs", UnprocessableEntity)
+	// ErrNoPackSizes is returned when no enabled pack size is configured.
+	ErrNoPackSizes = NewCustomError(ErrorNoPackSizesLabel, "no pack sizes configured", notFoundStatus)
+	// ErrOrderInfeasible is returned when no combination of the configured
+	// pack sizes covers the order.
+	ErrOrderInfeasible = NewCustomError(ErrorOrderInfeasibleLabel, "order cannot be fulfilled with the configured pack sizes", UnprocessableEntity)
+)
+
+// Validate checks a single pack; field names are relative to the pack.
+func (p SmartPack) Validate() error {
+	var errs FieldErrors
+	if p.Size <= 0 {
+		errs = append(errs, NewFieldError(ErrorInvalidPackSizeLabel, "size", BadRequestStatus))
+	}
+	if len(p.Label) > maxPackLabelLength {
+		errs = append(errs, NewFieldError(ErrorInvalidPackLabelLabel, "label", BadRequestStatus))
+	}
+	if p.SKU != "" && (len(p.SKU) > maxPackSKULength || !skuPattern.MatchString(p.SKU)) {
+		errs = append(errs, NewFieldError(ErrorInvalidPackSKULabel, "sku", BadRequestStatus))
+	}
+	if p.GTIN != "" && !IsValidGTIN(p.GTIN) {
+		errs = append(errs, NewFieldError(ErrorInvalidPackGTINLabel, "gtin", BadRequestStatus))
+	}
+	return errs.orNil()
+}
+
+// ValidatePackSizes checks a whole pack-size set before it replaces the
+// active one, and reports every invalid field.
+func ValidatePackSizes(packs []SmartPack, limits InputLimits) error {
+	if len(packs) == 0 {
+		return NewFieldError(ErrorInvalidRequestBodyParameter, "packs", BadRequestStatus)
+	}
+	if limits.MaxPackSizes > 0 && len(packs) > limits.MaxPackSizes {
+		return NewLimitError(ErrorPackSizesLimitLabel, "packs", int64(limits.MaxPackSizes), UnprocessableEntity)
+	}
+
+	var errs FieldErrors
+	seen := make(map[int]bool, len(packs))
+	for i, p := range packs {
+		for _, v := range CustomErrors(p.Validate()) {
+			errs = append(errs, NewFieldError(v.Label(), fmt.Sprintf("packs[%d].%s", i, v.Field()), v.Status()))
+		}
+		if p.Size <= 0 {
+			continue
+		}
+		if limits.MaxPackSize > 0 && p.Size > limits.MaxPackSize {
+			errs = append(errs, NewLimitError(ErrorPackSizeLimitLabel, fmt.Sprintf("packs[%d].size", i), int64(limits.MaxPackSize), UnprocessableEntity))
+		}
+		if seen[p.Size] {
+			errs = append(errs, NewFieldError(ErrorDuplicatePackSizeLabel, fmt.Sprintf("packs[%d].size", i), BadRequestStatus))
+		}
+		seen[p.Size] = true
+	}
+	return errs.orNil()
+}
+
+// IsValidGTIN reports whether s is a GTIN-8/12/13/14 with a correct check digit.
+func IsValidGTIN(s string) bool {
+	if !gtinLengths[len(s)] {
+		return false
+	}
+
+	sum := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		if i == len(s)-1 {
+			continue
+		}
+		digit := int(c - '0')
+		// weights alternate 3,1,3,... starting from the digit left of the check digit
+		if (len(s)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := (10 - sum%10) % 10
+	return int(s[len(s)-1]-'0') == check
+}
+
+// EnabledPackSizes returns the sizes that take part in pack calculations.
+func EnabledPackSizes(packs []SmartPack) []int {
+	sizes := make([]int, 0, len(packs))
+	for _, p := range packs {
+		if p.Enabled {
+			sizes = append(sizes, p.Size)
+		}
+	}
+	return sizes
+}
+
+// ApplyPackMetadata copies labels, SKUs and GTINs of the configured packs onto the solution details.
+func (s *PackSolution) ApplyPackMetadata(packs []SmartPack) {
+	bySize := make(map[int]SmartPack, len(packs))
+	for _, p := range packs {
+		bySize[p.Size] = p
+	}
+	for i, d := range s.PackDetails {
+		if p, ok := bySize[d.Size]; ok {
+			s.PackDetails[i].Label = p.Label
+			s.PackDetails[i].SKU = p.SKU
+			s.PackDetails[i].GTIN = p.GTIN
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidGTIN(t *testing.T) {
+	testCases := []struct {
+		name  string
+		gtin  string
+		valid bool
+	}{
+		{name: "gtin-8", gtin: "96385074", valid: true},
+		{name: "gtin-12", gtin: "036000291452", valid: true},
+		{name: "gtin-13", gtin: "4006381333931", valid: true},
+		{name: "gtin-14", gtin: "10012345678902", valid: true},
+		{name: "wrong check digit", gtin: "4006381333932", valid: false},
+		{name: "unsupported length", gtin: "400638133393", valid: false},
+		{name: "non digits", gtin: "40063813339A1", valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.valid, IsValidGTIN(tc.gtin))
+		})
+	}
+}
+
+func TestValidatePackSizes(t *testing.T) {
+	testCases := []struct {
+		name      string
+		packs     []SmartPack
+		wantLabel string
+		wantField string
+	}{
+		{name: "valid", packs: []SmartPack{{Size: 250, SKU: "BOX-250", GTIN: "96385074"}, {Size: 500}}},
+		{name: "empty", packs: nil, wantLabel: ErrorInvalidRequestBodyParameter, wantField: "packs"},
+		{name: "non positive size", packs: []SmartPack{{Size: 250}, {Size: 0}}, wantLabel: ErrorInvalidPackSizeLabel, wantField: "packs[1].size"},
+		{name: "duplicate size", packs: []SmartPack{{Size: 250}, {Size: 250}}, wantLabel: ErrorDuplicatePackSizeLabel, wantField: "packs[1].size"},
+		{name: "invalid sku", packs: []SmartPack{{Size: 250, SKU: "box 250"}}, wantLabel: ErrorInvalidPackSKULabel, wantField: "packs[0].sku"},
+		{name: "size above the limit", packs: []SmartPack{{Size: 250}, {Size: 1001}}, wantLabel: ErrorPackSizeLimitLabel, wantField: "packs[1].size"},
+		{name: "too many sizes", packs: []SmartPack{{Size: 1}, {Size: 2}, {Size: 3}, {Size: 4}}, wantLabel: ErrorPackSizesLimitLabel, wantField: "packs"},
+	}
+	limits := InputLimits{MaxPackSize: 1000, MaxPackSizes: 3}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidatePackSizes(tc.packs, limits)
+			if tc.wantLabel == "" {
+				require.NoError(t, err)
+				return
+			}
+			cerr, ok := IsHTTPCustomError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.wantLabel, cerr.Label())
+			require.Equal(t, tc.wantField, cerr.Field())
+		})
+	}
+
+	t.Run("every invalid field is reported", func(t *testing.T) {
+		err := ValidatePackSizes([]SmartPack{
+			{Size: 0, SKU: "box 1"},
+			{Size: 250, GTIN: "12345678"},
+			{Size: 250},
+		}, DefaultInputLimits)
+
+		var fields []string
+		for _, v := range CustomErrors(err) {
+			fields = append(fields, v.Field())
+		}
+		require.Equal(t, []string{"packs[0].size", "packs[0].sku", "packs[1].gtin", "packs[2].size"}, fields)
+	})
+}
+
+func TestInputLimits(t *testing.T) {
+	limits := InputLimits{MaxItemsOrdered: 1000}
+
+	require.NoError(t, limits.ValidateItemsOrdered(1000))
+	require.ErrorIs(t, limits.ValidateItemsOrdered(0), ErrInvalidItemsOrdered)
+
+	err := limits.ValidateItemsOrdered(1001)
+	require.ErrorIs(t, err, ErrItemsOrderedLimit)
+	require.EqualError(t, err, "items_ordered: exceeds the limit of 1000")
+	cerr, ok := IsHTTPCustomError(err)
+	require.True(t, ok)
+	require.Equal(t, int64(1000), cerr.Limit())
+	require.Equal(t, UnprocessableEntity, cerr.Status())
+
+	require.NoError(t, InputLimits{}.ValidateItemsOrdered(1_000_000_000), "zero disables a limit")
+}
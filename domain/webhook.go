@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	EventCalculationCompleted = "calculation.completed"
+
+	minWebhookSecretLength = 16
+)
+
+// WebhookEventTypes are the events partners can subscribe to.
+var WebhookEventTypes = []string{EventPackSizesChanged, EventCalculationCompleted}
+
+var (
+	ErrWebhookNotFound = NewCustomError(
+		ErrorWebhookNotFoundLabel,
+		"webhook subscription not found",
+		notFoundStatus,
+	)
+	ErrWebhookDeliveryNotFound = NewCustomError(
+		ErrorWebhookDeliveryNotFoundLabel,
+		"webhook delivery not found",
+		notFoundStatus,
+	)
+	// ErrWebhookDeliveryNotFailed is returned when replaying a delivery that
+	// is still pending or already succeeded.
+	ErrWebhookDeliveryNotFailed = NewCustomError(
+		ErrorWebhookDeliveryNotFailedLabel,
+		"only failed webhook deliveries can be replayed",
+		conflictStatus,
+	)
+)
+
+type WebhookSubscription struct {
+	ID         int64
+	URL        string
+	EventTypes []string
+	// Secret signs every delivery. It is only shown when the subscription
+	// is created.
+	Secret    string
+	CreatedAt time.Time
+}
+
+// NewWebhookSubscription validates a subscription request and generates a
+// secret when none is given.
+func NewWebhookSubscription(rawURL string, eventTypes []string, secret string) (WebhookSubscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return WebhookSubscription{}, NewFieldError(ErrorInvalidWebhookURLLabel, "url", BadRequestStatus)
+	}
+
+	if len(eventTypes) == 0 {
+		return WebhookSubscription{}, NewFieldError(ErrorInvalidWebhookEventLabel, "event_types", BadRequestStatus)
+	}
+	var types []string
+	for i, t := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, t) {
+			return WebhookSubscription{}, NewFieldError(
+				ErrorInvalidWebhookEventLabel, fmt.Sprintf("event_types[%d]", i), BadRequestStatus)
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+
+	if secret == "" {
+		secret, err = newWebhookSecret()
+		if err != nil {
+			return WebhookSubscription{}, err
+		}
+	} else if len(secret) < minWebhookSecretLength {
+		return WebhookSubscription{}, NewFieldError(ErrorInvalidWebhookSecretLabel, "secret", BadRequestStatus)
+	}
+
+	return WebhookSubscription{URL: rawURL, EventTypes: types, Secret: secret}, nil
+}
+
+func (s WebhookSubscription) Subscribes(eventType string) bool {
+	return slices.Contains(s.EventTypes, eventType)
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// WebhookEvent is the body of every webhook delivery. ID stays the same
+// across retries and replays, so receivers can drop duplicates.
+type WebhookEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewWebhookEventID returns a random event id for events that do not come
+// from the outbox.
+func NewWebhookEventID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
+
+// CalculationCompleted is the payload of an EventCalculationCompleted event.
+type CalculationCompleted struct {
+	ItemsOrdered int         `json:"items_ordered"`
+	TotalItems   int         `json:"total_items"`
+	TotalPacks   int         `json:"total_packs"`
+	Packs        map[int]int `json:"packs"`
+	RequestID    string      `json:"request_id,omitempty"`
+}
+
+func NewCalculationCompleted(solution *PackSolution, requestID string) CalculationCompleted {
+	return CalculationCompleted{
+		ItemsOrdered: solution.ItemsOrdered,
+		TotalItems:   solution.TotalItems,
+		TotalPacks:   solution.TotalPacks,
+		Packs:        solution.Packs,
+		RequestID:    requestID,
+	}
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent to one subscription. It stays pending
+// while attempts remain and ends up succeeded or failed.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	ResponseStatus int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// Retry records a failed attempt. The delivery is given up once it failed
+// maxAttempts times.
+func (d *WebhookDelivery) Retry(now time.Time, lastError string, responseStatus, maxAttempts int) {
+	d.Attempts++
+	d.LastError = lastError
+	d.ResponseStatus = responseStatus
+	if d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(OutboxRetryDelay(d.Attempts))
+}
+
+func (d *WebhookDelivery) Succeed(now time.Time, responseStatus int) {
+	d.Attempts++
+	d.Status = WebhookDeliverySucceeded
+	d.LastError = ""
+	d.ResponseStatus = responseStatus
+	d.DeliveredAt = &now
+}
+
+// Replay puts a failed delivery back in the queue with a fresh set of
+// attempts.
+func (d *WebhookDelivery) Replay(now time.Time) error {
+	if d.Status != WebhookDeliveryFailed {
+		return ErrWebhookDeliveryNotFailed
+	}
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	return nil
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID int64
+	Status         string
+	Limit          int
+	Offset         int
+}
+
+// SignWebhookPayload returns the signature header for payload sent at
+// timestamp: "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<payload>">".
+// Receivers recompute the HMAC with their secret and should reject old
+// timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookSubscription(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		URL        string
+		EventTypes []string
+		Secret     string
+		Field      string
+	}{
+		{
+			Name:       "valid",
+			URL:        "https://partner.example.com/hooks",
+			EventTypes: []string{EventPackSizesChanged},
+			Secret:     "0123456789abcdef",
+		},
+		{
+			Name:       "relative url",
+			URL:        "/hooks",
+			EventTypes: []string{EventPackSizesChanged},
+			Field:      "url",
+		},
+		{
+			Name:       "unsupported scheme",
+			URL:        "ftp://partner.example.com/hooks",
+			EventTypes: []string{EventPackSizesChanged},
+			Field:      "url",
+		},
+		{
+			Name:  "no event types",
+			URL:   "https://partner.example.com/hooks",
+			Field: "event_types",
+		},
+		{
+			Name:       "unknown event type",
+			URL:        "https://partner.example.com/hooks",
+			EventTypes: []string{EventPackSizesChanged, "order.created"},
+			Field:      "event_types[1]",
+		},
+		{
+			Name:       "short secret",
+			URL:        "https://partner.example.com/hooks",
+			EventTypes: []string{EventPackSizesChanged},
+			Secret:     "short",
+			Field:      "secret",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sub, err := NewWebhookSubscription(tc.URL, tc.EventTypes, tc.Secret)
+			if tc.Field == "" {
+				require.NoError(t, err)
+				require.Equal(t, tc.Secret, sub.Secret)
+				return
+			}
+			v, ok := IsHTTPCustomError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.Field, v.Field())
+			require.Equal(t, BadRequestStatus, v.Status())
+		})
+	}
+
+	t.Run("generates a secret and drops duplicate event types", func(t *testing.T) {
+		sub, err := NewWebhookSubscription("http://localhost:9000/hooks",
+			[]string{EventCalculationCompleted, EventCalculationCompleted}, "")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(sub.Secret, "whsec_"))
+		require.Equal(t, []string{EventCalculationCompleted}, sub.EventTypes)
+		require.True(t, sub.Subscribes(EventCalculationCompleted))
+		require.False(t, sub.Subscribes(EventPackSizesChanged))
+	})
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("whsec_test_secret_1234",
+		time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC), []byte(`{"id":"evt_1"}`))
+	require.Equal(t, "t=1751284800,v1=23bfdd53020f92beb90b2a044ced945ce65a33d44d9f516011001ad9b04495ea", signature)
+}
+
+func TestWebhookDeliveryLifecycle(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	d := WebhookDelivery{Status: WebhookDeliveryPending}
+
+	require.ErrorIs(t, d.Replay(now), ErrWebhookDeliveryNotFailed)
+
+	d.Retry(now, "webhook responded with 503", 503, 2)
+	require.Equal(t, WebhookDeliveryPending, d.Status)
+	require.Equal(t, now.Add(time.Second), d.NextAttemptAt)
+
+	d.Retry(now, "connection refused", 0, 2)
+	require.Equal(t, WebhookDeliveryFailed, d.Status)
+	require.Equal(t, 2, d.Attempts)
+	require.Equal(t, "connection refused", d.LastError)
+
+	later := now.Add(time.Hour)
+	require.NoError(t, d.Replay(later))
+	require.Equal(t, WebhookDeliveryPending, d.Status)
+	require.Zero(t, d.Attempts)
+	require.Equal(t, later, d.NextAttemptAt)
+
+	d.Succeed(later, 204)
+	require.Equal(t, WebhookDeliverySucceeded, d.Status)
+	require.Equal(t, 204, d.ResponseStatus)
+	require.Empty(t, d.LastError)
+	require.Equal(t, &later, d.DeliveredAt)
+}
@@ -0,0 +1,28 @@
+// Package broker is the small publish/subscribe surface the worker needs, so
+// the message broker can be swapped without touching the consumers.
+package broker
+
+import "context"
+
+type Message struct {
+	Subject string
+	// Reply, when set, is where the sender expects the answer.
+	Reply  string
+	Header map[string]string
+	Data   []byte
+}
+
+// Handler processes one message. Brokers call the handlers of a
+// subscription one at a time.
+type Handler func(ctx context.Context, msg Message)
+
+type Broker interface {
+	// Subscribe delivers messages on subject to h until ctx is done or the
+	// broker is closed. Subscriptions sharing a queue group split the
+	// messages between them instead of each receiving all of them.
+	Subscribe(ctx context.Context, subject, queue string, h Handler) error
+	Publish(ctx context.Context, msg Message) error
+	// Close stops the subscriptions, waits for the handlers in flight and
+	// flushes pending publishes.
+	Close() error
+}
@@ -0,0 +1,147 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("broker is closed")
+
+// MemoryBroker is an in-process Broker for tests and local development.
+// Every subscription has its own goroutine; messages published before a
+// subscription exists are dropped, as with core NATS.
+type MemoryBroker struct {
+	mu      sync.Mutex
+	subs    map[string][]*memorySubscription
+	next    map[string]int
+	closed  bool
+	running sync.WaitGroup
+}
+
+type memorySubscription struct {
+	queue    string
+	messages chan Message
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (s *memorySubscription) stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subs: make(map[string][]*memorySubscription),
+		next: make(map[string]int),
+	}
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, subject, queue string, h Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+
+	sub := &memorySubscription{
+		queue:    queue,
+		messages: make(chan Message, 64),
+		done:     make(chan struct{}),
+	}
+	b.subs[subject] = append(b.subs[subject], sub)
+
+	b.running.Add(1)
+	go func() {
+		defer b.running.Done()
+		defer b.unsubscribe(subject, sub)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.done:
+				// Closing: finish what was already delivered.
+				for {
+					select {
+					case msg := <-sub.messages:
+						h(ctx, msg)
+					default:
+						return
+					}
+				}
+			case msg := <-sub.messages:
+				h(ctx, msg)
+			}
+		}
+	}()
+	return nil
+}
+
+// Publish hands msg to every plain subscription on its subject and to one
+// subscription per queue group, round robin.
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+
+	var targets []*memorySubscription
+	groups := make(map[string][]*memorySubscription)
+	for _, sub := range b.subs[msg.Subject] {
+		if sub.queue == "" {
+			targets = append(targets, sub)
+			continue
+		}
+		groups[sub.queue] = append(groups[sub.queue], sub)
+	}
+	for queue, members := range groups {
+		key := msg.Subject + "\x00" + queue
+		targets = append(targets, members[b.next[key]%len(members)])
+		b.next[key]++
+	}
+	b.mu.Unlock()
+
+	for _, sub := range targets {
+		select {
+		case sub.messages <- msg:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for _, subs := range b.subs {
+		for _, sub := range subs {
+			sub.stop()
+		}
+	}
+	b.mu.Unlock()
+
+	b.running.Wait()
+	return nil
+}
+
+func (b *MemoryBroker) unsubscribe(subject string, sub *memorySubscription) {
+	sub.stop()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[subject]
+	for i, s := range subs {
+		if s == sub {
+			b.subs[subject] = append(subs[:i:i], subs[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package broker_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/pkg/broker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBroker(t *testing.T) {
+	t.Run("queue groups split messages, plain subscriptions get all", func(t *testing.T) {
+		b := broker.NewMemoryBroker()
+		ctx := context.Background()
+
+		var mu sync.Mutex
+		got := make(map[string]int)
+		record := func(name string) broker.Handler {
+			return func(ctx context.Context, msg broker.Message) {
+				mu.Lock()
+				got[name]++
+				mu.Unlock()
+			}
+		}
+		require.NoError(t, b.Subscribe(ctx, "orders", "workers", record("worker-1")))
+		require.NoError(t, b.Subscribe(ctx, "orders", "workers", record("worker-2")))
+		require.NoError(t, b.Subscribe(ctx, "orders", "", record("audit")))
+		require.NoError(t, b.Subscribe(ctx, "other", "", record("other")))
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, b.Publish(ctx, broker.Message{Subject: "orders", Data: []byte("{}")}))
+		}
+		require.NoError(t, b.Close(), "close waits for the handlers")
+
+		require.Equal(t, map[string]int{"worker-1": 2, "worker-2": 2, "audit": 4}, got)
+	})
+
+	t.Run("a cancelled subscription stops receiving", func(t *testing.T) {
+		b := broker.NewMemoryBroker()
+		defer b.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		received := make(chan broker.Message, 1)
+		require.NoError(t, b.Subscribe(ctx, "orders", "", func(ctx context.Context, msg broker.Message) {
+			received <- msg
+		}))
+		cancel()
+
+		// Unsubscribing happens on the subscription goroutine.
+		require.Eventually(t, func() bool {
+			require.NoError(t, b.Publish(context.Background(), broker.Message{Subject: "orders"}))
+			select {
+			case <-received:
+				return false
+			case <-time.After(10 * time.Millisecond):
+				return true
+			}
+		}, time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("a closed broker rejects publishes and subscriptions", func(t *testing.T) {
+		b := broker.NewMemoryBroker()
+		require.NoError(t, b.Close())
+
+		ctx := context.Background()
+		require.ErrorIs(t, b.Publish(ctx, broker.Message{Subject: "orders"}), broker.ErrClosed)
+		require.ErrorIs(t, b.Subscribe(ctx, "orders", "", func(context.Context, broker.Message) {}), broker.ErrClosed)
+	})
+}
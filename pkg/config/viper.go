@@ -26,5 +26,8 @@ func (c *ViperConfig) LoadConfig(path string, cfg config.Config) error {
 		return err
 	}
 
-	return viper.Unmarshal(cfg)
+	if err := viper.Unmarshal(cfg); err != nil {
+		return err
+	}
+	return cfg.Validate()
 }
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// RegisterDatabaseStats exports the connection pool stats returned by stats,
+// read on every scrape. It must be called at most once.
+func RegisterDatabaseStats(stats func() domain.DatabaseStats) {
+	registry.MustRegister(databaseCollector{stats: stats})
+}
+
+func databaseDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+}
+
+var (
+	dbMaxConns             = databaseDesc("max_conns", "Maximum size of the pool.")
+	dbTotalConns           = databaseDesc("total_conns", "Connections in the pool.")
+	dbIdleConns            = databaseDesc("idle_conns", "Idle connections in the pool.")
+	dbAcquiredConns        = databaseDesc("acquired_conns", "Connections in use.")
+	dbConstructingConns    = databaseDesc("constructing_conns", "Connections being opened.")
+	dbAcquires             = databaseDesc("acquires_total", "Connections acquired from the pool.")
+	dbEmptyAcquires        = databaseDesc("empty_acquires_total", "Acquires that waited for a connection because none was idle.")
+	dbCanceledAcquires     = databaseDesc("canceled_acquires_total", "Acquires canceled before a connection was available.")
+	dbAcquireDurationTotal = databaseDesc("acquire_duration_seconds_total", "Time spent acquiring connections.")
+)
+
+type databaseCollector struct {
+	stats func() domain.DatabaseStats
+}
+
+func (c databaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		dbMaxConns, dbTotalConns, dbIdleConns, dbAcquiredConns, dbConstructingConns,
+		dbAcquires, dbEmptyAcquires, dbCanceledAcquires, dbAcquireDurationTotal,
+	} {
+		ch <- desc
+	}
+}
+
+func (c databaseCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stats()
+	gauge := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v)
+	}
+	counter := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v)
+	}
+
+	gauge(dbMaxConns, float64(s.MaxConns))
+	gauge(dbTotalConns, float64(s.TotalConns))
+	gauge(dbIdleConns, float64(s.IdleConns))
+	gauge(dbAcquiredConns, float64(s.AcquiredConns))
+	gauge(dbConstructingConns, float64(s.ConstructingConns))
+	counter(dbAcquires, float64(s.AcquireCount))
+	counter(dbEmptyAcquires, float64(s.EmptyAcquireCount))
+	counter(dbCanceledAcquires, float64(s.CanceledAcquireCount))
+	counter(dbAcquireDurationTotal, s.AcquireDuration.Seconds())
+}
@@ -0,0 +1,85 @@
+// Package metrics collects the Prometheus metrics served on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "smartpack"
+
+// registry holds the metrics of this package only, unlike the default
+// registry that libraries may add to.
+var registry = prometheus.NewRegistry()
+
+var (
+	handlerDuration = promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "cqrs",
+		Name:      "handler_duration_seconds",
+		Help:      "Duration of command and query handlers.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind", "handler"})
+	handlerErrors = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cqrs",
+		Name:      "handler_errors_total",
+		Help:      "Command and query handlers that returned an error.",
+	}, []string{"kind", "handler"})
+
+	calculationItemsOrdered = promauto.With(registry).NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "calculator",
+		Name:      "items_ordered",
+		Help:      "Order size of pack calculations.",
+		Buckets:   prometheus.ExponentialBuckets(10, 10, 6),
+	})
+	calculationTableEntries = promauto.With(registry).NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "calculator",
+		Name:      "table_entries",
+		Help:      "Entries in the dynamic-programming table of pack calculations, the order plus the largest pack size.",
+		Buckets:   prometheus.ExponentialBuckets(10, 10, 7),
+	})
+	calculationDuration = promauto.With(registry).NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "calculator",
+		Name:      "duration_seconds",
+		Help:      "Duration of pack calculations.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHandler records a command or query handler run. kind is "command"
+// or "query".
+func ObserveHandler(kind, handler string, duration time.Duration, failed bool) {
+	handlerDuration.WithLabelValues(kind, handler).Observe(duration.Seconds())
+	if failed {
+		handlerErrors.WithLabelValues(kind, handler).Inc()
+	}
+}
+
+// ObserveCalculation records a pack calculation over a table of tableEntries
+// entries.
+func ObserveCalculation(itemsOrdered, tableEntries int, duration time.Duration) {
+	calculationItemsOrdered.Observe(float64(itemsOrdered))
+	calculationTableEntries.Observe(float64(tableEntries))
+	calculationDuration.Observe(duration.Seconds())
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	api := chi.NewRouter()
+	api.Use(HTTPMiddleware)
+	api.Get("/webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	router := chi.NewRouter()
+	router.Mount("/api", api)
+
+	for _, path := range []string{"/api/webhooks/1", "/api/webhooks/2", "/api/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, 2.0, testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/api/webhooks/{id}", "204")))
+	require.Equal(t, 0.0, testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/api/missing", "404")),
+		"paths without a route must not get their own series")
+}
+
+func TestObserveHandler(t *testing.T) {
+	ObserveHandler("command", "TestCommand", time.Millisecond, false)
+	ObserveHandler("command", "TestCommand", time.Millisecond, true)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(handlerErrors.WithLabelValues("command", "TestCommand")))
+	require.Equal(t, 1, testutil.CollectAndCount(handlerDuration, "smartpack_cqrs_handler_duration_seconds"))
+}
+
+func TestDatabaseCollector(t *testing.T) {
+	collector := databaseCollector{stats: func() domain.DatabaseStats {
+		return domain.DatabaseStats{MaxConns: 10, AcquiredConns: 2, AcquireCount: 42, AcquireDuration: 1500 * time.Millisecond}
+	}}
+
+	expected := `
+# HELP smartpack_db_pool_acquired_conns Connections in use.
+# TYPE smartpack_db_pool_acquired_conns gauge
+smartpack_db_pool_acquired_conns 2
+# HELP smartpack_db_pool_acquires_total Connections acquired from the pool.
+# TYPE smartpack_db_pool_acquires_total counter
+smartpack_db_pool_acquires_total 42
+# HELP smartpack_db_pool_acquire_duration_seconds_total Time spent acquiring connections.
+# TYPE smartpack_db_pool_acquire_duration_seconds_total counter
+smartpack_db_pool_acquire_duration_seconds_total 1.5
+# HELP smartpack_db_pool_max_conns Maximum size of the pool.
+# TYPE smartpack_db_pool_max_conns gauge
+smartpack_db_pool_max_conns 10
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"smartpack_db_pool_acquired_conns",
+		"smartpack_db_pool_acquires_total",
+		"smartpack_db_pool_acquire_duration_seconds_total",
+		"smartpack_db_pool_max_conns",
+	))
+}
+
+func TestHandler(t *testing.T) {
+	ObserveCalculation(1200, 6201, time.Millisecond)
+
+	rw := httptest.NewRecorder()
+	Handler().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Contains(t, rw.Body.String(), "smartpack_calculator_table_entries_count 1")
+	require.Contains(t, rw.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,207 @@
+package packio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+// Decode reads packs in the given format and validates every row with the
+// same rules SetPackSizes applies. The returned error is reserved for files
+// that cannot be parsed at all; rejected rows are reported in Decoded.Errors
+// and left out of Decoded.Packs.
+func Decode(r io.Reader, format Format) (Decoded, error) {
+	var (
+		records   []Record
+		rowErrors []RowError
+		err       error
+	)
+
+	switch format {
+	case FormatCSV:
+		records, rowErrors, err = decodeCSV(r)
+	case FormatJSON, FormatYAML:
+		records, err = decodeDocument(r, format)
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return Decoded{}, err
+	}
+
+	packs, validationErrors := validateRecords(records, rowErrors)
+	rowErrors = append(rowErrors, validationErrors...)
+	sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+	if len(records) == 0 && len(rowErrors) == 0 {
+		rowErrors = append(rowErrors, RowError{
+			Label:   domain.ErrorInvalidRequestBodyParameter,
+			Message: "file contains no pack sizes",
+		})
+	}
+	return Decoded{Packs: packs, Rows: len(records), Errors: rowErrors}, nil
+}
+
+func decodeDocument(r io.Reader, format Format) ([]Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatYAML {
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("invalid yaml: %w", err)
+		}
+	}
+
+	var doc Document
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid %s document: %w", format, err)
+	}
+	return doc.Packs, nil
+}
+
+func decodeCSV(r io.Reader) ([]Record, []RowError, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	columns, err := csvColumns(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	cr.FieldsPerRecord = len(header)
+
+	var (
+		records   []Record
+		rowErrors []RowError
+	)
+	for row := 1; ; row++ {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Row: row, Label: domain.ErrorInvalidRequestBodyParameter, Message: err.Error()})
+			records = append(records, Record{})
+			continue
+		}
+
+		record, rowErr := csvRecord(row, columns, fields)
+		if rowErr != nil {
+			rowErrors = append(rowErrors, *rowErr)
+		}
+		records = append(records, record)
+	}
+	return records, rowErrors, nil
+}
+
+func csvColumns(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(csvHeader))
+	for _, h := range csvHeader {
+		known[h] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown csv column %q", h)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["size"]; !ok {
+		return nil, errors.New("csv header must contain a size column")
+	}
+	return columns, nil
+}
+
+func csvRecord(row int, columns map[string]int, fields []string) (Record, *RowError) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+
+	record := Record{Label: get("label"), SKU: get("sku"), GTIN: get("gtin")}
+
+	size, err := strconv.Atoi(get("size"))
+	if err != nil {
+		return record, &RowError{Row: row, Field: "size", Label: domain.ErrorInvalidPackSizeLabel, Message: "size must be an integer"}
+	}
+	record.Size = size
+
+	if raw := get("enabled"); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return record, &RowError{Row: row, Field: "enabled", Label: domain.ErrorInvalidRequestBodyParameter, Message: "enabled must be true or false"}
+		}
+		record.Enabled = &enabled
+	}
+	return record, nil
+}
+
+// validateRecords returns the packs of the valid rows. It skips rows that
+// already failed to parse so each row is reported once.
+func validateRecords(records []Record, parseErrors []RowError) ([]domain.SmartPack, []RowError) {
+	failed := make(map[int]bool, len(parseErrors))
+	for _, e := range parseErrors {
+		failed[e.Row] = true
+	}
+
+	packs := make([]domain.SmartPack, 0, len(records))
+	seen := make(map[int]int, len(records))
+	var rowErrors []RowError
+
+	for i, record := range records {
+		row := i + 1
+		pack := record.toDomain()
+		if failed[row] {
+			continue
+		}
+		if err := pack.Validate(); err != nil {
+			rowErrors = append(rowErrors, rowErrorsFromDomain(row, err)...)
+			continue
+		}
+		if first, ok := seen[pack.Size]; ok {
+			rowErrors = append(rowErrors, RowError{
+				Row:     row,
+				Field:   "size",
+				Label:   domain.ErrorDuplicatePackSizeLabel,
+				Message: fmt.Sprintf("size %d already defined in row %d", pack.Size, first),
+			})
+			continue
+		}
+		seen[pack.Size] = row
+		packs = append(packs, pack)
+	}
+	return packs, rowErrors
+}
+
+func rowErrorsFromDomain(row int, err error) []RowError {
+	cerrs := domain.CustomErrors(err)
+	if len(cerrs) == 0 {
+		return []RowError{{Row: row, Label: domain.ErrorInvalidRequestBodyParameter, Message: err.Error()}}
+	}
+
+	rowErrors := make([]RowError, 0, len(cerrs))
+	for _, v := range cerrs {
+		rowErrors = append(rowErrors, RowError{Row: row, Field: v.Field(), Label: v.Label(), Message: v.Error()})
+	}
+	return rowErrors
+}
@@ -0,0 +1,56 @@
+package packio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+var csvHeader = []string{"size", "label", "sku", "gtin", "enabled"}
+
+// Encode writes packs in the given format.
+func Encode(w io.Writer, format Format, packs []domain.SmartPack) error {
+	switch format {
+	case FormatCSV:
+		return encodeCSV(w, packs)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(newDocument(packs))
+	case FormatYAML:
+		out, err := yaml.Marshal(newDocument(packs))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	return ErrUnsupportedFormat
+}
+
+func newDocument(packs []domain.SmartPack) Document {
+	doc := Document{Packs: make([]Record, 0, len(packs))}
+	for _, p := range packs {
+		doc.Packs = append(doc.Packs, recordFromDomain(p))
+	}
+	return doc
+}
+
+func encodeCSV(w io.Writer, packs []domain.SmartPack) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, p := range packs {
+		row := []string{strconv.Itoa(p.Size), p.Label, p.SKU, p.GTIN, strconv.FormatBool(p.Enabled)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,129 @@
+// Package packio reads and writes pack-size configuration files in CSV, JSON and YAML.
+package packio
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/rossi1/smart-pack/domain"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported pack-size file format")
+
+// Record is a single pack in a configuration file.
+type Record struct {
+	Size    int    `json:"size"`
+	Label   string `json:"label,omitempty"`
+	SKU     string `json:"sku,omitempty"`
+	GTIN    string `json:"gtin,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+// Document is the JSON and YAML file layout; it matches the packs field of POST /pack-sizes.
+type Document struct {
+	Packs []Record `json:"packs"`
+}
+
+// Decoded is a decoded file: the packs of its valid rows, the number of rows
+// read and why the others were rejected.
+type Decoded struct {
+	Packs  []domain.SmartPack
+	Rows   int
+	Errors []RowError
+}
+
+// RowError describes why a row of an imported file was rejected. Rows are 1-based.
+type RowError struct {
+	Row     int
+	Field   string
+	Label   string
+	Message string
+}
+
+func (e RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+	}
+	return fmt.Sprintf("row %d: %s: %s", e.Row, e.Field, e.Message)
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, s)
+}
+
+// FormatFromContentType maps a request or part Content-Type to a format.
+func FormatFromContentType(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, contentType)
+	}
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return FormatCSV, nil
+	case "application/json":
+		return FormatJSON, nil
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, contentType)
+}
+
+// FormatFromFilename derives the format from a file extension.
+func FormatFromFilename(name string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatYAML:
+		return "application/yaml"
+	default:
+		return "application/json"
+	}
+}
+
+func (f Format) Extension() string {
+	return string(f)
+}
+
+func recordFromDomain(p domain.SmartPack) Record {
+	enabled := p.Enabled
+	return Record{
+		Size:    p.Size,
+		Label:   p.Label,
+		SKU:     p.SKU,
+		GTIN:    p.GTIN,
+		Enabled: &enabled,
+	}
+}
+
+func (r Record) toDomain() domain.SmartPack {
+	return domain.SmartPack{
+		Size:    r.Size,
+		Label:   r.Label,
+		SKU:     r.SKU,
+		GTIN:    r.GTIN,
+		Enabled: r.Enabled == nil || *r.Enabled,
+	}
+}
@@ -0,0 +1,106 @@
+package packio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	packs := []domain.SmartPack{
+		{Size: 250, Label: "Small box", SKU: "BOX-250", GTIN: "4006381333931", Enabled: true},
+		{Size: 500, Label: "Medium, padded", Enabled: false},
+	}
+
+	for _, format := range []Format{FormatCSV, FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Encode(&buf, format, packs))
+
+			decoded, err := Decode(&buf, format)
+			require.NoError(t, err)
+			require.Empty(t, decoded.Errors)
+			require.Equal(t, packs, decoded.Packs)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name       string
+		format     Format
+		input      string
+		expectErr  bool
+		wantPacks  int
+		wantRows   int
+		wantErrors []RowError
+	}{
+		{
+			name:      "csv with columns in any order and default enabled",
+			format:    FormatCSV,
+			input:     "sku,size\nBOX-250,250\nBOX-500,500\n",
+			wantPacks: 2,
+			wantRows:  2,
+		},
+		{
+			name:      "csv with unknown column",
+			format:    FormatCSV,
+			input:     "size,colour\n250,red\n",
+			expectErr: true,
+		},
+		{
+			name:      "csv reports every invalid row once",
+			format:    FormatCSV,
+			input:     "size,gtin,enabled\nabc,,\n250,4006381333932,\n250,,maybe\n500,,\n500,,\n",
+			wantPacks: 1,
+			wantRows:  5,
+			wantErrors: []RowError{
+				{Row: 1, Field: "size", Label: domain.ErrorInvalidPackSizeLabel},
+				{Row: 2, Field: "gtin", Label: domain.ErrorInvalidPackGTINLabel},
+				{Row: 3, Field: "enabled", Label: domain.ErrorInvalidRequestBodyParameter},
+				{Row: 5, Field: "size", Label: domain.ErrorDuplicatePackSizeLabel},
+			},
+		},
+		{
+			name:       "empty json document",
+			format:     FormatJSON,
+			input:      `{"packs": []}`,
+			wantErrors: []RowError{{Row: 0, Label: domain.ErrorInvalidRequestBodyParameter}},
+		},
+		{
+			name:      "json with unknown field",
+			format:    FormatJSON,
+			input:     `{"packs": [{"size": 250, "colour": "red"}]}`,
+			expectErr: true,
+		},
+		{
+			name:      "yaml",
+			format:    FormatYAML,
+			input:     "packs:\n  - size: 250\n    enabled: false\n  - size: 500\n",
+			wantPacks: 2,
+			wantRows:  2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := Decode(strings.NewReader(tc.input), tc.format)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, decoded.Packs, tc.wantPacks)
+			require.Equal(t, tc.wantRows, decoded.Rows)
+			require.Len(t, decoded.Errors, len(tc.wantErrors))
+			for i, want := range tc.wantErrors {
+				require.Equal(t, want.Row, decoded.Errors[i].Row)
+				require.Equal(t, want.Field, decoded.Errors[i].Field)
+				require.Equal(t, want.Label, decoded.Errors[i].Label)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,7 +15,10 @@ import (
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
+	"github.com/rossi1/smart-pack/api"
 	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/pkg/metrics"
+	"github.com/rossi1/smart-pack/pkg/tracing"
 	"github.com/sirupsen/logrus"
 )
 
@@ -33,18 +37,30 @@ func RunHTTPServer(
 
 	startCtx, startCancel := context.WithCancel(ctx)
 
+	shutdown := make(chan struct{})
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%s", port),
 		Handler:           GetRootRouter(cfg, basePath, swaggerPath, createHandler),
 		WriteTimeout:      cfg.ApplicationAPITimeout,
 		ReadTimeout:       cfg.ApplicationAPITimeout,
 		ReadHeaderTimeout: cfg.ApplicationAPITimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return WithShutdown(context.Background(), shutdown)
+		},
 	}
 
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		<-osChan
 		logrus.WithContext(startCtx).Debug("Server is shutting down...")
 
+		// Readiness fails from here on, and event streams end because
+		// Shutdown waits for active requests. Requests are still served
+		// until load balancers have noticed.
+		close(shutdown)
+		time.Sleep(cfg.ShutdownDrainPeriod)
+
 		ctxWithTimeout, cancel := context.WithTimeout(ctx, teardownTimeout*time.Second)
 		defer cancel()
 
@@ -62,6 +78,9 @@ func RunHTTPServer(
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		logrus.WithContext(startCtx).WithError(err).Fatal("Server shutting down, error occurred")
 	}
+	// ListenAndServe returns as soon as Shutdown starts; wait for the active
+	// requests to finish.
+	<-stopped
 }
 
 func GetRootRouter(
@@ -78,7 +97,11 @@ func GetRootRouter(
 	rootRouter.HandleFunc("/docs/", ServeSwaggerUI)
 
 	// Serve Swagger JSON
-	rootRouter.Get(refineSwaggerPath(swaggerPath), Swagger)
+	rootRouter.Get(refineSwaggerPath(swaggerPath), SwaggerHandler(cfg.APISpecPath))
+
+	if cfg.MetricsEnabled {
+		rootRouter.Method(http.MethodGet, "/metrics", metrics.Handler())
+	}
 
 	if apiHandler != nil {
 		router := chi.NewRouter()
@@ -99,8 +122,16 @@ func refineSwaggerPath(path string) string {
 func setMiddlewares(cfg *appConfig.AppConfig, router *chi.Mux) {
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(RequestMetadata)
+	if cfg.TracingExporter != "" {
+		router.Use(tracing.HTTPMiddleware)
+	}
+	if cfg.MetricsEnabled {
+		// Before Recoverer, so that panics are counted as 500s.
+		router.Use(metrics.HTTPMiddleware)
+	}
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(cfg.ApplicationAPITimeout))
+	router.Use(timeoutUnlessStreaming(cfg.ApplicationAPITimeout))
 	router.Use(middleware.DefaultLogger)
 
 	addCorsMiddleware(router, cfg.CORSAllowedOrigins)
@@ -121,35 +152,40 @@ func addCorsMiddleware(router *chi.Mux, origins string) {
 	corsMiddleware := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-App-ID"},
-		ExposedHeaders:   []string{"Link", "X-Total-Count"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-App-ID", "X-API-Key", ActorHeader, "traceparent", "tracestate"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
 	router.Use(corsMiddleware.Handler)
 }
 
-func Swagger(w http.ResponseWriter, r *http.Request) {
-	file, err := os.ReadFile("./api/api.yml")
-	if err != nil {
-		logrus.WithContext(r.Context()).WithError(err).Info("failed to read api.yml")
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	j, err := yaml.YAMLToJSON(file)
+// SwaggerHandler serves the OpenAPI spec as JSON. It reads specPath on every
+// request, so edits show up without a restart, or uses the embedded spec when
+// specPath is empty.
+func SwaggerHandler(specPath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file := api.Spec
+		if specPath != "" {
+			var err error
+			if file, err = os.ReadFile(specPath); err != nil {
+				logrus.WithContext(r.Context()).WithError(err).Info("failed to read api.yml")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
 
-	if err != nil {
-		logrus.WithContext(r.Context()).WithError(err).Info("failed to convert yaml to json")
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	_, err = w.Write(j)
-	if err != nil {
-		logrus.WithContext(r.Context()).WithError(err).Info("failed to write api.yml to response")
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		j, err := yaml.YAMLToJSON(file)
+		if err != nil {
+			logrus.WithContext(r.Context()).WithError(err).Info("failed to convert yaml to json")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err = w.Write(j); err != nil {
+			logrus.WithContext(r.Context()).WithError(err).Info("failed to write api.yml to response")
+		}
 	}
-	w.WriteHeader(http.StatusOK)
 }
 
 func ServeSwaggerUI(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwaggerHandler(t *testing.T) {
+	t.Run("embedded spec", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		SwaggerHandler("")(rw, httptest.NewRequest(http.MethodGet, "/api/v1/api-docs/swagger.json", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		var spec map[string]any
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &spec))
+		require.Contains(t, spec, "openapi")
+	})
+
+	t.Run("override path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "api.yml")
+		require.NoError(t, os.WriteFile(path, []byte("openapi: 3.0.0\ninfo:\n  title: dev\n"), 0o600))
+
+		rw := httptest.NewRecorder()
+		SwaggerHandler(path)(rw, httptest.NewRequest(http.MethodGet, "/api/v1/api-docs/swagger.json", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.JSONEq(t, `{"openapi":"3.0.0","info":{"title":"dev"}}`, rw.Body.String())
+	})
+
+	t.Run("missing override", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		SwaggerHandler(filepath.Join(t.TempDir(), "missing.yml"))(rw,
+			httptest.NewRequest(http.MethodGet, "/api/v1/api-docs/swagger.json", nil))
+
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+}
@@ -16,6 +16,8 @@ type ErrorMessageBody struct {
 type Message struct {
 	FormProperty string `json:"formProperty"`
 	Label        string `json:"label"`
+	// Limit is the configured limit an input exceeded, if any.
+	Limit int64 `json:"limit,omitempty"`
 }
 
 func NewErrorMessage(label, formProperty string) []Message {
@@ -53,9 +55,20 @@ func WithStatus(label, formProperty string, err error, w http.ResponseWriter, r
 	httpRespondWithError(err, w, r, status, m)
 }
 
+// WithMessages responds with several messages at once, e.g. one per invalid
+// field.
+func WithMessages(m []Message, err error, w http.ResponseWriter, r *http.Request, status int) {
+	httpRespondWithError(err, w, r, status, m)
+}
+
 func httpRespondWithError(err error, w http.ResponseWriter, r *http.Request, statusCode int, m []Message) {
 	ctx := r.Context()
 	logrus.WithContext(ctx).Error(err)
+	if AcceptsProblem(r) {
+		writeProblem(w, newProblem(err, r, statusCode, m))
+		return
+	}
+
 	resp := ErrorMessageBody{
 		Code:     statusCode,
 		Messages: m,
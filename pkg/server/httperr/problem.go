@@ -0,0 +1,77 @@
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+const (
+	// ProblemContentType is the RFC 7807 media type. Clients that accept it
+	// get a Problem instead of an ErrorMessageBody.
+	ProblemContentType = "application/problem+json"
+
+	problemTypePrefix = "urn:smart-pack:"
+)
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	// Type identifies the problem by the label of its first error.
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance is the request ID, also found in the logs and the audit log.
+	Instance string         `json:"instance,omitempty"`
+	Errors   []ProblemError `json:"errors"`
+}
+
+type ProblemError struct {
+	Field string `json:"field,omitempty"`
+	Label string `json:"label"`
+	Limit int64  `json:"limit,omitempty"`
+}
+
+// AcceptsProblem reports whether the client asked for problem details.
+func AcceptsProblem(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ProblemContentType)
+}
+
+func newProblem(err error, r *http.Request, statusCode int, m []Message) Problem {
+	p := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Instance: middleware.GetReqID(r.Context()),
+		Errors:   make([]ProblemError, 0, len(m)),
+	}
+	if len(m) > 0 {
+		p.Type = problemTypePrefix + m[0].Label
+	}
+	p.Detail = problemDetail(err)
+	for _, msg := range m {
+		p.Errors = append(p.Errors, ProblemError{Field: msg.FormProperty, Label: msg.Label, Limit: msg.Limit})
+	}
+	return p
+}
+
+// problemDetail describes err by its domain errors only. Whatever they wrap,
+// such as driver or decoder messages, stays in the logs, where the request ID
+// leads to it.
+func problemDetail(err error) string {
+	cerrs := domain.CustomErrors(err)
+	details := make([]string, 0, len(cerrs))
+	for _, cerr := range cerrs {
+		details = append(details, cerr.Error())
+	}
+	return strings.Join(details, "; ")
+}
+
+func writeProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
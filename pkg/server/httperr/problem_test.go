@@ -0,0 +1,100 @@
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponseNegotiation(t *testing.T) {
+	messages := []Message{
+		{FormProperty: "packs[0].size", Label: "error_invalid_pack_size"},
+		{FormProperty: "packs[1].gtin", Label: "error_invalid_pack_gtin"},
+	}
+
+	t.Run("default body", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		WithMessages(messages, errors.New("invalid packs"), rw, httptest.NewRequest(http.MethodPost, "/pack-sizes", nil), http.StatusBadRequest)
+
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+		var body ErrorMessageBody
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		require.Equal(t, ErrorMessageBody{Code: http.StatusBadRequest, Messages: messages}, body)
+	})
+
+	t.Run("problem details", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/pack-sizes", nil)
+		r.Header.Set("Accept", "application/problem+json, application/json")
+		rw := httptest.NewRecorder()
+
+		err := domain.FieldErrors{
+			domain.NewFieldError("error_invalid_pack_size", "packs[0].size", domain.BadRequestStatus),
+			domain.NewFieldError("error_invalid_pack_gtin", "packs[1].gtin", domain.BadRequestStatus),
+		}
+		middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			WithMessages(messages, err, w, r, http.StatusBadRequest)
+		})).ServeHTTP(rw, r)
+
+		require.Equal(t, ProblemContentType, rw.Header().Get("Content-Type"))
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &problem))
+		require.NotEmpty(t, problem.Instance)
+		problem.Instance = ""
+		require.Equal(t, Problem{
+			Type:   "urn:smart-pack:error_invalid_pack_size",
+			Title:  "Bad Request",
+			Status: http.StatusBadRequest,
+			Detail: "packs[0].size: error_invalid_pack_size; packs[1].gtin: error_invalid_pack_gtin",
+			Errors: []ProblemError{
+				{Field: "packs[0].size", Label: "error_invalid_pack_size"},
+				{Field: "packs[1].gtin", Label: "error_invalid_pack_gtin"},
+			},
+		}, problem)
+	})
+
+	t.Run("wrapped errors stay out of the detail", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/pack-sizes", nil)
+		r.Header.Set("Accept", ProblemContentType)
+		rw := httptest.NewRecorder()
+
+		err := fmt.Errorf("%w: %w", domain.ErrConflict, errors.New(`ERROR: could not serialize access (SQLSTATE 40001)`))
+		WithStatus(domain.ErrorConflictLabel, "", err, rw, r, http.StatusConflict)
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &problem))
+		require.Equal(t, domain.ErrConflict.Error(), problem.Detail)
+	})
+
+	t.Run("decoder errors have no detail", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/pack-sizes", nil)
+		r.Header.Set("Accept", ProblemContentType)
+		rw := httptest.NewRecorder()
+
+		UnprocessableEntity(domain.ErrorUnprocessableEntityLabel, "", errors.New("json: cannot unmarshal string into Go struct field"), rw, r)
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &problem))
+		require.Empty(t, problem.Detail)
+	})
+
+	t.Run("server errors hide the detail", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/pack-sizes", nil)
+		r.Header.Set("Accept", ProblemContentType)
+		rw := httptest.NewRecorder()
+
+		InternalError("error_internal_server_error", "", errors.New("dial tcp 10.0.0.3:5432: connection refused"), rw, r)
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &problem))
+		require.Equal(t, http.StatusInternalServerError, problem.Status)
+		require.Empty(t, problem.Detail)
+	})
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/rossi1/smart-pack/common/requestmeta"
+)
+
+// ActorHeader names the caller as the client claims it. It is recorded in the
+// audit log as the claimed actor, next to the authenticated one.
+const ActorHeader = "X-Actor"
+
+// RequestMetadata stores the claimed actor, source IP and request ID on the
+// request context. The actor is left to authentication. It must run after
+// middleware.RequestID and middleware.RealIP.
+func RequestMetadata(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestmeta.WithMetadata(r.Context(), requestmeta.Metadata{
+			ClaimedActor: r.Header.Get(ActorHeader),
+			SourceIP:     sourceIP(r.RemoteAddr),
+			RequestID:    middleware.GetReqID(r.Context()),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// sourceIP strips the port that RemoteAddr carries when RealIP found no
+// forwarding header.
+func sourceIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// EventStreamContentType is the media type of Server-Sent Events.
+const EventStreamContentType = "text/event-stream"
+
+type shutdownKey struct{}
+
+// WithShutdown returns a context whose ShuttingDown channel is shutdown.
+func WithShutdown(ctx context.Context, shutdown <-chan struct{}) context.Context {
+	return context.WithValue(ctx, shutdownKey{}, shutdown)
+}
+
+// ShuttingDown returns a channel that is closed when the server starts
+// shutting down, before the drain period. Long-lived responses such as event
+// streams must return then, because Shutdown waits for them. Outside
+// RunHTTPServer the channel is nil and never closes.
+func ShuttingDown(ctx context.Context) <-chan struct{} {
+	shutdown, _ := ctx.Value(shutdownKey{}).(<-chan struct{})
+	return shutdown
+}
+
+// AcceptsEventStream reports whether the client asked for Server-Sent Events.
+func AcceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), EventStreamContentType)
+}
+
+// streamingRoutes are the event streams of the API, by method and path below
+// its base path. They stay open until the client leaves or the server shuts
+// down, so the request timeout does not apply to them.
+var streamingRoutes = map[string]bool{
+	http.MethodGet + " /pack-sizes/stream": true,
+}
+
+// timeoutUnlessStreaming applies the request timeout to everything except
+// streamingRoutes. It goes by the route rather than the Accept header, which
+// the client could send anywhere.
+func timeoutUnlessStreaming(timeout time.Duration) func(http.Handler) http.Handler {
+	withTimeout := middleware.Timeout(timeout)
+	return func(next http.Handler) http.Handler {
+		timed := withTimeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if streamingRoutes[r.Method+" "+routePath(r)] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			timed.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routePath is the path of r below the router the middleware runs in, which
+// is mounted at the API base path.
+func routePath(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		return rctx.RoutePath
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutUnlessStreaming(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Method      string
+		Path        string
+		ExpectTimed bool
+	}{
+		{Name: "regular request", Method: http.MethodPost, Path: "/calculate", ExpectTimed: true},
+		{Name: "event stream", Method: http.MethodGet, Path: "/pack-sizes/stream", ExpectTimed: false},
+		{Name: "event stream mounted below the base path", Method: http.MethodGet, Path: "/api/v1/pack-sizes/stream", ExpectTimed: false},
+		{Name: "other method on the stream path", Method: http.MethodPost, Path: "/pack-sizes/stream", ExpectTimed: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var timedOut bool
+			api := chi.NewRouter()
+			api.Use(timeoutUnlessStreaming(10 * time.Millisecond))
+			api.HandleFunc("/*", func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+					timedOut = true
+				case <-time.After(100 * time.Millisecond):
+				}
+			})
+			root := chi.NewRouter()
+			root.Mount("/api/v1", api)
+			root.Mount("/", api)
+
+			// The header must not lift the timeout on other routes.
+			req := httptest.NewRequest(tc.Method, tc.Path, nil)
+			req.Header.Set("Accept", EventStreamContentType)
+			root.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, tc.ExpectTimed, timedOut)
+		})
+	}
+}
+
+func TestShuttingDown(t *testing.T) {
+	require.Nil(t, ShuttingDown(context.Background()))
+
+	shutdown := make(chan struct{})
+	ctx := WithShutdown(context.Background(), shutdown)
+	close(shutdown)
+
+	select {
+	case <-ShuttingDown(ctx):
+	default:
+		t.Fatal("expected the shutdown channel to be closed")
+	}
+}
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+
+	api := chi.NewRouter()
+	api.Use(HTTPMiddleware)
+	api.Get("/webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, child := Tracer().Start(r.Context(), "child")
+		child.End()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	router := chi.NewRouter()
+	router.Mount("/api", api)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/webhooks/1", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+
+	require.Equal(t, "GET /api/webhooks/{id}", server.Name())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String(),
+		"the trace of the caller is continued")
+	require.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+	require.Equal(t, codes.Error, server.Status().Code)
+	attrs := spanAttributes(server)
+	require.Equal(t, "/api/webhooks/{id}", attrs["http.route"].AsString())
+	require.Equal(t, int64(http.StatusInternalServerError), attrs["http.response.status_code"].AsInt64())
+}
+
+func TestPgxTracer(t *testing.T) {
+	recorder := recordSpans(t)
+	tracer := PgxTracer{}
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "\n\t\tselect size FROM pack_sizes WHERE retired_at IS NULL",
+		Args: []any{"secret"},
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "INSERT INTO audit_log VALUES ($1)"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("unique violation")})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "postgres SELECT", spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code, "no rows is not a failure")
+	attrs := spanAttributes(spans[0])
+	require.Equal(t, "postgresql", attrs["db.system"].AsString())
+	for _, v := range attrs {
+		require.NotContains(t, v.Emit(), "secret", "arguments are not recorded")
+	}
+
+	require.Equal(t, "postgres INSERT", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,223 @@
+// Package messaging consumes order events from a message broker, the
+// messaging counterpart of the REST port.
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rossi1/smart-pack/app"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/broker"
+	"github.com/sirupsen/logrus"
+)
+
+// Headers set on published messages. A dead-lettered message keeps the
+// original payload and carries why it was rejected.
+const (
+	OrderIDHeader          = "X-Order-Id"
+	DeadLetterReasonHeader = "X-Dead-Letter-Reason"
+	DeadLetterErrorHeader  = "X-Dead-Letter-Error"
+	OriginalSubjectHeader  = "X-Original-Subject"
+)
+
+type Subjects struct {
+	// Orders is consumed by the Queue group.
+	Orders string
+	Queue  string
+	// Results receives the pack plans of messages without a reply subject.
+	Results string
+	// DeadLetter receives the messages that could not be processed. They
+	// are only logged when it is empty.
+	DeadLetter string
+}
+
+// Retry is how an order that fails for a reason other than the message, such
+// as the database being unreachable, is calculated again. Core NATS does not
+// redeliver, so the consumer waits Backoff, doubled after every attempt, up
+// to Attempts calculations in all.
+type Retry struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
+type OrderCreated struct {
+	OrderID      string `json:"order_id"`
+	ItemsOrdered int    `json:"items_ordered"`
+}
+
+type PackPlan struct {
+	OrderID      string         `json:"order_id"`
+	ItemsOrdered int            `json:"items_ordered"`
+	TotalItems   int            `json:"total_items"`
+	TotalPacks   int            `json:"total_packs"`
+	Packs        []PackPlanLine `json:"packs"`
+}
+
+type PackPlanLine struct {
+	Size     int    `json:"size"`
+	Quantity int    `json:"quantity"`
+	Label    string `json:"label,omitempty"`
+	SKU      string `json:"sku,omitempty"`
+	GTIN     string `json:"gtin,omitempty"`
+}
+
+type OrderConsumer struct {
+	app      *app.Application
+	broker   broker.Broker
+	subjects Subjects
+	retry    Retry
+}
+
+func NewOrderConsumer(application *app.Application, b broker.Broker, subjects Subjects, retry Retry) *OrderConsumer {
+	return &OrderConsumer{app: application, broker: b, subjects: subjects, retry: retry}
+}
+
+// Start subscribes concurrency handlers to the order subject. They share the
+// queue group, so every order is handled once per group.
+func (c *OrderConsumer) Start(ctx context.Context, concurrency int) error {
+	for i := 0; i < max(concurrency, 1); i++ {
+		if err := c.broker.Subscribe(ctx, c.subjects.Orders, c.subjects.Queue, c.Handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle calculates the pack plan of one order-created message and publishes
+// it to the message's reply subject, or to the results subject. Invalid
+// messages are dead-lettered at once; other failures are retried and only
+// dead-lettered once the retries are used up or the worker stops.
+func (c *OrderConsumer) Handle(ctx context.Context, msg broker.Message) {
+	order, err := decodeOrderCreated(msg.Data)
+	if err != nil {
+		c.deadLetter(ctx, msg, err)
+		return
+	}
+
+	result, err := c.calculate(ctx, order)
+	if err != nil {
+		c.deadLetter(ctx, msg, err)
+		return
+	}
+
+	data, err := json.Marshal(newPackPlan(order.OrderID, result))
+	if err != nil {
+		c.deadLetter(ctx, msg, err)
+		return
+	}
+
+	subject := c.subjects.Results
+	if msg.Reply != "" {
+		subject = msg.Reply
+	}
+	err = c.broker.Publish(ctx, broker.Message{
+		Subject: subject,
+		Header:  map[string]string{OrderIDHeader: order.OrderID},
+		Data:    data,
+	})
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).WithField("order_id", order.OrderID).
+			Error("Failed to publish pack plan")
+	}
+}
+
+func (c *OrderConsumer) calculate(ctx context.Context, order OrderCreated) (*domain.PackSolution, error) {
+	backoff := c.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		result, err := c.app.Queries.CalculatePacks.Handle(ctx, &query.CalculatePacksQuery{ItemsOrdered: order.ItemsOrdered})
+		if err == nil || isInvalidOrder(err) || attempt >= c.retry.Attempts {
+			return result, err
+		}
+
+		logrus.WithContext(ctx).WithError(err).WithField("order_id", order.OrderID).
+			Warnf("Failed to calculate order, retrying in %s", backoff)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isInvalidOrder reports whether the order itself cannot be calculated, so
+// retrying it would fail the same way.
+func isInvalidOrder(err error) bool {
+	return errors.Is(err, domain.ErrInvalidItemsOrdered) ||
+		errors.Is(err, domain.ErrItemsOrderedLimit) ||
+		errors.Is(err, domain.ErrOrderInfeasible)
+}
+
+// deadLetter republishes msg unchanged to the dead-letter subject. The
+// reason is the error label, or error_internal_server_error for failures
+// that are not the message's fault; those can be republished once fixed.
+func (c *OrderConsumer) deadLetter(ctx context.Context, msg broker.Message, cause error) {
+	reason := domain.ErrorInternalServerErrorLabel
+	log := logrus.WithContext(ctx).WithError(cause).WithField("subject", msg.Subject)
+	if v, ok := domain.IsHTTPCustomError(cause); ok {
+		reason = v.Label()
+		log.Warn("Rejected order message")
+	} else {
+		log.Error("Failed to process order message")
+	}
+
+	if c.subjects.DeadLetter == "" {
+		return
+	}
+
+	header := make(map[string]string, len(msg.Header)+3)
+	for k, v := range msg.Header {
+		header[k] = v
+	}
+	header[DeadLetterReasonHeader] = reason
+	header[DeadLetterErrorHeader] = cause.Error()
+	header[OriginalSubjectHeader] = msg.Subject
+
+	err := c.broker.Publish(ctx, broker.Message{
+		Subject: c.subjects.DeadLetter,
+		Header:  header,
+		Data:    msg.Data,
+	})
+	if err != nil {
+		log.WithField("publish_error", err).Error("Failed to dead-letter order message")
+	}
+}
+
+func decodeOrderCreated(data []byte) (OrderCreated, error) {
+	var order OrderCreated
+	if err := json.Unmarshal(data, &order); err != nil {
+		return OrderCreated{}, domain.NewCustomError(domain.ErrorUnprocessableEntityLabel, err.Error(), domain.UnprocessableEntity)
+	}
+	if strings.TrimSpace(order.OrderID) == "" {
+		return OrderCreated{}, domain.NewFieldError(domain.ErrorInvalidOrderIDLabel, "order_id", domain.BadRequestStatus)
+	}
+	if order.ItemsOrdered <= 0 {
+		return OrderCreated{}, domain.ErrInvalidItemsOrdered
+	}
+	return order, nil
+}
+
+func newPackPlan(orderID string, result *domain.PackSolution) PackPlan {
+	plan := PackPlan{
+		OrderID:      orderID,
+		ItemsOrdered: result.ItemsOrdered,
+		TotalItems:   result.TotalItems,
+		TotalPacks:   result.TotalPacks,
+		Packs:        make([]PackPlanLine, 0, len(result.PackDetails)),
+	}
+	for _, d := range result.PackDetails {
+		plan.Packs = append(plan.Packs, PackPlanLine{
+			Size:     d.Size,
+			Quantity: d.Quantity,
+			Label:    d.Label,
+			SKU:      d.SKU,
+			GTIN:     d.GTIN,
+		})
+	}
+	return plan
+}
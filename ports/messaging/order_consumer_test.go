@@ -0,0 +1,217 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/app"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/broker"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetry = Retry{Attempts: 3, Backoff: time.Millisecond}
+
+var testSubjects = Subjects{
+	Orders:     "orders.created",
+	Queue:      "workers",
+	Results:    "pack_plans",
+	DeadLetter: "orders.dead_letter",
+}
+
+type testConsumer struct {
+	consumer   *OrderConsumer
+	broker     *broker.MemoryBroker
+	repo       *query.MockGetPackSizesRepository
+	calculator *query.MockPackCalculator
+}
+
+func newTestConsumer(t *testing.T) testConsumer {
+	ctrl := gomock.NewController(t)
+	repo := query.NewMockGetPackSizesRepository(ctrl)
+	calculator := query.NewMockPackCalculator(ctrl)
+	application := &app.Application{
+		Queries: &app.Queries{
+			CalculatePacks: query.NewCalculatePacksHandler(query.NewGetPackSizesHandler(repo), calculator, domain.DefaultInputLimits),
+		},
+	}
+
+	b := broker.NewMemoryBroker()
+	t.Cleanup(func() { b.Close() })
+	return testConsumer{
+		consumer:   NewOrderConsumer(application, b, testSubjects, testRetry),
+		broker:     b,
+		repo:       repo,
+		calculator: calculator,
+	}
+}
+
+// collect returns the messages published on subject.
+func collect(t *testing.T, b broker.Broker, subject string) <-chan broker.Message {
+	t.Helper()
+
+	messages := make(chan broker.Message, 10)
+	require.NoError(t, b.Subscribe(context.Background(), subject, "", func(ctx context.Context, msg broker.Message) {
+		messages <- msg
+	}))
+	return messages
+}
+
+func receive(t *testing.T, messages <-chan broker.Message) broker.Message {
+	t.Helper()
+
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message published")
+		return broker.Message{}
+	}
+}
+
+func TestOrderConsumer(t *testing.T) {
+	solution := func() *domain.PackSolution {
+		return &domain.PackSolution{
+			ItemsOrdered: 263,
+			TotalItems:   500,
+			TotalPacks:   1,
+			Packs:        map[int]int{500: 1},
+			PackDetails:  []domain.PackDetail{{Size: 500, Quantity: 1}},
+		}
+	}
+
+	t.Run("publishes the pack plan to the results subject", func(t *testing.T) {
+		tc := newTestConsumer(t)
+		results := collect(t, tc.broker, testSubjects.Results)
+		require.NoError(t, tc.consumer.Start(context.Background(), 2))
+
+		tc.repo.EXPECT().GetPackSizes(gomock.Any()).
+			Return([]domain.SmartPack{{Size: 250, Enabled: true}, {Size: 500, SKU: "BOX-500", Enabled: true}}, nil)
+		tc.calculator.EXPECT().Calculate(gomock.Any(), 263, []int{250, 500}).Return(solution(), nil)
+
+		require.NoError(t, tc.broker.Publish(context.Background(), broker.Message{
+			Subject: testSubjects.Orders,
+			Data:    []byte(`{"order_id": "ord-1", "items_ordered": 263}`),
+		}))
+
+		msg := receive(t, results)
+		require.Equal(t, "ord-1", msg.Header[OrderIDHeader])
+		var plan PackPlan
+		require.NoError(t, json.Unmarshal(msg.Data, &plan))
+		require.Equal(t, PackPlan{
+			OrderID:      "ord-1",
+			ItemsOrdered: 263,
+			TotalItems:   500,
+			TotalPacks:   1,
+			Packs:        []PackPlanLine{{Size: 500, Quantity: 1, SKU: "BOX-500"}},
+		}, plan)
+	})
+
+	t.Run("answers on the reply subject when one is given", func(t *testing.T) {
+		tc := newTestConsumer(t)
+		replies := collect(t, tc.broker, "_INBOX.42")
+
+		tc.repo.EXPECT().GetPackSizes(gomock.Any()).Return([]domain.SmartPack{{Size: 500, Enabled: true}}, nil)
+		tc.calculator.EXPECT().Calculate(gomock.Any(), 263, []int{500}).Return(solution(), nil)
+
+		tc.consumer.Handle(context.Background(), broker.Message{
+			Subject: testSubjects.Orders,
+			Reply:   "_INBOX.42",
+			Data:    []byte(`{"order_id": "ord-1", "items_ordered": 263}`),
+		})
+		require.Equal(t, "ord-1", receive(t, replies).Header[OrderIDHeader])
+	})
+
+	t.Run("retries an order that failed for another reason", func(t *testing.T) {
+		tc := newTestConsumer(t)
+		results := collect(t, tc.broker, testSubjects.Results)
+
+		gomock.InOrder(
+			tc.repo.EXPECT().GetPackSizes(gomock.Any()).Return(nil, errors.New("connection refused")),
+			tc.repo.EXPECT().GetPackSizes(gomock.Any()).Return([]domain.SmartPack{{Size: 500, Enabled: true}}, nil),
+		)
+		tc.calculator.EXPECT().Calculate(gomock.Any(), 263, []int{500}).Return(solution(), nil)
+
+		tc.consumer.Handle(context.Background(), broker.Message{
+			Subject: testSubjects.Orders,
+			Data:    []byte(`{"order_id": "ord-1", "items_ordered": 263}`),
+		})
+		require.Equal(t, "ord-1", receive(t, results).Header[OrderIDHeader])
+	})
+
+	deadLetterCases := []struct {
+		Name     string
+		Data     string
+		MockFunc func(tc testConsumer)
+		Reason   string
+	}{
+		{
+			Name:   "malformed json",
+			Data:   `{"order_id": "ord-1", "items_ordered": "many"}`,
+			Reason: domain.ErrorUnprocessableEntityLabel,
+		},
+		{
+			Name:   "missing order id",
+			Data:   `{"items_ordered": 263}`,
+			Reason: domain.ErrorInvalidOrderIDLabel,
+		},
+		{
+			Name:   "no items",
+			Data:   `{"order_id": "ord-1", "items_ordered": 0}`,
+			Reason: domain.ErrorInvalidItemsOrderedLabel,
+		},
+		{
+			Name: "infeasible order without retrying",
+			Data: `{"order_id": "ord-1", "items_ordered": 263}`,
+			MockFunc: func(tc testConsumer) {
+				tc.repo.EXPECT().GetPackSizes(gomock.Any()).Return([]domain.SmartPack{{Size: 500, Enabled: true}}, nil).Times(1)
+				tc.calculator.EXPECT().Calculate(gomock.Any(), 263, []int{500}).Return(nil, domain.ErrOrderInfeasible).Times(1)
+			},
+			Reason: domain.ErrorOrderInfeasibleLabel,
+		},
+		{
+			Name: "pack sizes unavailable after the retries",
+			Data: `{"order_id": "ord-1", "items_ordered": 263}`,
+			MockFunc: func(tc testConsumer) {
+				tc.repo.EXPECT().GetPackSizes(gomock.Any()).Return(nil, errors.New("connection refused")).Times(testRetry.Attempts)
+			},
+			Reason: domain.ErrorInternalServerErrorLabel,
+		},
+		{
+			Name: "no pack sizes after the retries",
+			Data: `{"order_id": "ord-1", "items_ordered": 263}`,
+			MockFunc: func(tc testConsumer) {
+				tc.repo.EXPECT().GetPackSizes(gomock.Any()).Return(nil, nil).Times(testRetry.Attempts)
+				tc.calculator.EXPECT().Calculate(gomock.Any(), 263, gomock.Any()).Return(nil, domain.ErrNoPackSizes).Times(testRetry.Attempts)
+			},
+			Reason: domain.ErrorNoPackSizesLabel,
+		},
+	}
+	for _, c := range deadLetterCases {
+		t.Run("dead-letters "+c.Name, func(t *testing.T) {
+			tc := newTestConsumer(t)
+			deadLetters := collect(t, tc.broker, testSubjects.DeadLetter)
+			if c.MockFunc != nil {
+				c.MockFunc(tc)
+			}
+
+			tc.consumer.Handle(context.Background(), broker.Message{
+				Subject: testSubjects.Orders,
+				Header:  map[string]string{"X-Request-Id": "req-1"},
+				Data:    []byte(c.Data),
+			})
+
+			msg := receive(t, deadLetters)
+			require.Equal(t, c.Data, string(msg.Data))
+			require.Equal(t, c.Reason, msg.Header[DeadLetterReasonHeader])
+			require.NotEmpty(t, msg.Header[DeadLetterErrorHeader])
+			require.Equal(t, testSubjects.Orders, msg.Header[OriginalSubjectHeader])
+			require.Equal(t, "req-1", msg.Header["X-Request-Id"])
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/rossi1/smart-pack/common/requestmeta"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/sirupsen/logrus"
+)
+
+// Authenticator identifies the caller of a request. It returns a nil
+// principal when the request carries no credentials of its kind, and
+// domain.ErrUnauthenticated when they are invalid.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*domain.Principal, error)
+}
+
+// authenticate requires credentials on every operation the API spec
+// secures and records the caller in the request metadata. What the caller
+// may do is checked by the application. It runs before rateLimit, which
+// needs the caller, so failed attempts are limited here instead: each takes
+// a request from the source IP's failures bucket, and once that is empty
+// they get 429 rather than 401.
+func authenticate(authenticators []Authenticator, limiter RateLimiter, failures domain.RateLimit) ports.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if ctx.Value(ports.ApiKeyAuthScopes) == nil && ctx.Value(ports.BearerAuthScopes) == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := authenticateRequest(authenticators, r)
+			if err != nil {
+				if limiter != nil && failures.Limit > 0 && !allowAuthFailure(w, r, limiter, failures) {
+					respondWithError(w, r, domain.ErrRateLimited, "Too many failed authentications")
+					return
+				}
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				respondWithError(w, r, err, "Failed to authenticate request")
+				return
+			}
+
+			meta := requestmeta.FromContext(ctx)
+			meta.Actor = principal.Subject
+			meta.Principal = principal
+			next.ServeHTTP(w, r.WithContext(requestmeta.WithMetadata(ctx, meta)))
+		})
+	}
+}
+
+// authenticateRequest returns the principal of the first authenticator that
+// finds credentials in r.
+func authenticateRequest(authenticators []Authenticator, r *http.Request) (*domain.Principal, error) {
+	for _, a := range authenticators {
+		principal, err := a.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			return principal, nil
+		}
+	}
+	return nil, domain.ErrUnauthenticated
+}
+
+// allowAuthFailure takes a request from the failures bucket of r's source IP
+// and sets Retry-After when there was none left. When the limiter fails, the
+// failure is let through.
+func allowAuthFailure(w http.ResponseWriter, r *http.Request, limiter RateLimiter, failures domain.RateLimit) bool {
+	ctx := r.Context()
+	d, err := limiter.Take(ctx, "auth_failure ip:"+requestmeta.FromContext(ctx).SourceIP, failures)
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).Warn("Rate limiter failed, not limiting failed authentication")
+		return true
+	}
+	if !d.Allowed {
+		w.Header().Set("Retry-After", seconds(d.RetryAfter))
+	}
+	return d.Allowed
+}
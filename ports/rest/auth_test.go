@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerAuthentication(t *testing.T) {
+	keys, err := adapters.NewAPIKeyAuthenticator("dashboard:viewer:view-key,ops:admin:admin-key")
+	require.NoError(t, err)
+
+	newHandler := func(t *testing.T) (http.Handler, *mockedDependencies) {
+		testServer := newTestAPIServer(t)
+		return server.RequestMetadata(Handler(testServer.api.app, chi.NewRouter(), HandlerOptions{Authenticators: []Authenticator{keys}})), testServer.deps
+	}
+	serve := func(handler http.Handler, r *http.Request, key string) *httptest.ResponseRecorder {
+		if key != "" {
+			r.Header.Set(adapters.APIKeyHeader, key)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+	requireLabel := func(t *testing.T, rw *httptest.ResponseRecorder, status int, label string) {
+		t.Helper()
+		require.Equal(t, status, rw.Code)
+		var body httperr.ErrorMessageBody
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		require.Equal(t, httperr.NewErrorMessage(label, ""), body.Messages)
+	}
+
+	t.Run("health is open", func(t *testing.T) {
+		handler, _ := newHandler(t)
+
+		rw := serve(handler, httptest.NewRequest(http.MethodGet, "/health", nil), "")
+
+		require.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		handler, _ := newHandler(t)
+
+		rw := serve(handler, httptest.NewRequest(http.MethodGet, "/pack-sizes", nil), "")
+
+		requireLabel(t, rw, http.StatusUnauthorized, domain.ErrorUnauthenticatedLabel)
+		require.Equal(t, "Bearer", rw.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		handler, _ := newHandler(t)
+
+		rw := serve(handler, httptest.NewRequest(http.MethodGet, "/pack-sizes", nil), "guess")
+
+		requireLabel(t, rw, http.StatusUnauthorized, domain.ErrorUnauthenticatedLabel)
+	})
+
+	t.Run("viewer reads pack sizes", func(t *testing.T) {
+		handler, deps := newHandler(t)
+		deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+			EXPECT().GetPackSizes(gomock.Any()).
+			Return([]domain.SmartPack{{Size: 250, Enabled: true}}, nil)
+
+		rw := serve(handler, httptest.NewRequest(http.MethodGet, "/pack-sizes", nil), "view-key")
+
+		require.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("viewer cannot set pack sizes", func(t *testing.T) {
+		handler, _ := newHandler(t)
+		r := httptest.NewRequest(http.MethodPost, "/pack-sizes", strings.NewReader(`{"pack_sizes":[250]}`))
+		r.Header.Set("Content-Type", "application/json")
+
+		rw := serve(handler, r, "view-key")
+
+		requireLabel(t, rw, http.StatusForbidden, domain.ErrorForbiddenLabel)
+	})
+
+	t.Run("admin sets pack sizes as itself", func(t *testing.T) {
+		handler, deps := newHandler(t)
+		deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository).
+			EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		deps.mockedAppendAuditRepository.(*command.MockAuditRepository).
+			EXPECT().AppendAuditEntry(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, entry domain.AuditEntry) error {
+				require.Equal(t, "ops", entry.Actor)
+				require.Equal(t, "someone-else", entry.ClaimedActor)
+				return nil
+			})
+		deps.mockedOutboxRepository.(*command.MockOutboxRepository).
+			EXPECT().AppendOutboxEvent(gomock.Any(), gomock.Any()).
+			Return(nil)
+		r := httptest.NewRequest(http.MethodPost, "/pack-sizes", strings.NewReader(`{"pack_sizes":[250]}`))
+		r.Header.Set("Content-Type", "application/json")
+		// The authenticated subject is the actor; a self-declared one is only
+		// recorded as claimed.
+		r.Header.Set(server.ActorHeader, "someone-else")
+
+		rw := serve(handler, r, "admin-key")
+
+		require.Less(t, rw.Code, 300, rw.Body.String())
+	})
+}
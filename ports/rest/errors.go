@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/sirupsen/logrus"
+)
+
+// respondWithError writes err as an error response, listing every invalid
+// field of a domain.FieldErrors. Domain errors carry their own label, field
+// and status: 400 for invalid input, 404 for something missing, 409 for a
+// conflicting change, 413 for a body above the limit and 422 for a request
+// that is valid but cannot be served. A list with several statuses answers
+// with the lowest, so malformed input is reported as such whatever order the
+// fields were checked in. Errors for inputs above a configured limit name it.
+// Anything else is logged as msg and becomes a 500.
+func respondWithError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if cerrs := domain.CustomErrors(err); len(cerrs) > 0 {
+		messages := make([]httperr.Message, 0, len(cerrs))
+		status := cerrs[0].Status()
+		for _, v := range cerrs {
+			messages = append(messages, httperr.Message{FormProperty: v.Field(), Label: v.Label(), Limit: v.Limit()})
+			status = min(status, v.Status())
+		}
+		httperr.WithMessages(messages, err, w, r, status)
+		return
+	}
+
+	logrus.WithContext(r.Context()).WithError(err).Error(msg)
+	httperr.InternalError(domain.ErrorInternalServerErrorLabel, "", err, w, r)
+}
+
+// handleRequestError answers requests the generated router rejected, such as
+// a malformed path or query parameter.
+func handleRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	httperr.BadRequest(domain.ErrorInvalidRequestParameterLabel, paramName(err), err, w, r)
+}
+
+func paramName(err error) string {
+	var (
+		formatErr    *ports.InvalidParamFormatError
+		requiredErr  *ports.RequiredParamError
+		headerErr    *ports.RequiredHeaderError
+		unmarshalErr *ports.UnmarshalingParamError
+		tooManyErr   *ports.TooManyValuesForParamError
+		cookieErr    *ports.UnescapedCookieParamError
+	)
+	switch {
+	case errors.As(err, &formatErr):
+		return formatErr.ParamName
+	case errors.As(err, &requiredErr):
+		return requiredErr.ParamName
+	case errors.As(err, &headerErr):
+		return headerErr.ParamName
+	case errors.As(err, &unmarshalErr):
+		return unmarshalErr.ParamName
+	case errors.As(err, &tooManyErr):
+		return tooManyErr.ParamName
+	case errors.As(err, &cookieErr):
+		return cookieErr.ParamName
+	}
+	return ""
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondWithErrorMixedStatuses(t *testing.T) {
+	invalid := domain.NewFieldError(domain.ErrorInvalidPackSKULabel, "packs[0].sku", domain.BadRequestStatus)
+	tooLarge := domain.NewLimitError(domain.ErrorPackSizeLimitLabel, "packs[1].size", 1000, domain.UnprocessableEntity)
+
+	for name, err := range map[string]domain.FieldErrors{
+		"invalid first":   {invalid, tooLarge},
+		"too large first": {tooLarge, invalid},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			respondWithError(rw, httptest.NewRequest(http.MethodPost, "/pack-sizes", nil), err, "Failed")
+
+			require.Equal(t, http.StatusBadRequest, rw.Code)
+			require.Contains(t, rw.Body.String(), domain.ErrorInvalidPackSKULabel)
+			require.Contains(t, rw.Body.String(), domain.ErrorPackSizeLimitLabel)
+		})
+	}
+}
@@ -3,7 +3,11 @@ package rest
 import (
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/rossi1/smart-pack/app"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server/dto"
+	"github.com/rossi1/smart-pack/ports"
 )
 
 const SwaggerPath = "/api/v1/api-docs/swagger.json"
@@ -18,11 +22,67 @@ func NewHTTPServer(application *app.Application) *HTTPServer {
 	}
 }
 
+// HandlerOptions are the optional protections of the API.
+type HandlerOptions struct {
+	// Authenticators identify callers; secured operations require
+	// credentials one of them accepts. Without any, the API is open.
+	Authenticators []Authenticator
+	// RateLimiter enforces RateLimits. Without one, requests are not
+	// limited.
+	RateLimiter RateLimiter
+	RateLimits  RateLimits
+	// MaxBodyBytes caps request bodies; zero leaves them unlimited.
+	MaxBodyBytes int64
+}
+
+// Handler routes the API onto router. Requests that the generated code
+// rejects get the same error responses as the rest of the API.
+func Handler(application *app.Application, router chi.Router, opts HandlerOptions) http.Handler {
+	// The generated code wraps the handler in these in order, so the last
+	// one runs first: callers are known before they are rate limited, and
+	// only then is the body read.
+	var middlewares []ports.MiddlewareFunc
+	if opts.MaxBodyBytes > 0 {
+		middlewares = append(middlewares, limitBody(opts.MaxBodyBytes))
+	}
+	if opts.RateLimiter != nil {
+		middlewares = append(middlewares, rateLimit(opts.RateLimiter, opts.RateLimits))
+	}
+	if len(opts.Authenticators) > 0 {
+		middlewares = append(middlewares, authenticate(opts.Authenticators, opts.RateLimiter, opts.RateLimits.AuthFailures))
+	}
+	return ports.HandlerWithOptions(NewHTTPServer(application), ports.ChiServerOptions{
+		BaseRouter:       router,
+		Middlewares:      middlewares,
+		ErrorHandlerFunc: handleRequestError,
+	})
+}
+
 func (h HTTPServer) Ping(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("pong")) //nolint:errcheck
 }
 
 func (h HTTPServer) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+	resp := ports.HealthResponse{Status: "healthy"}
+	if h.app.DatabaseHealth != nil {
+		stats := mapDatabaseStatsToResponse(h.app.DatabaseHealth.Stats())
+		resp.Database = &stats
+	}
+
+	dto.Write(w, r, resp)
+}
+
+func mapDatabaseStatsToResponse(s domain.DatabaseStats) ports.DatabaseStats {
+	return ports.DatabaseStats{
+		MaxConns:             s.MaxConns,
+		TotalConns:           s.TotalConns,
+		IdleConns:            s.IdleConns,
+		AcquiredConns:        s.AcquiredConns,
+		ConstructingConns:    s.ConstructingConns,
+		AcquireCount:         s.AcquireCount,
+		EmptyAcquireCount:    s.EmptyAcquireCount,
+		CanceledAcquireCount: s.CanceledAcquireCount,
+		AcquireDurationMs:    s.AcquireDuration.Milliseconds(),
+	}
 }
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server/dto"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/rossi1/smart-pack/ports"
+)
+
+func (s *HTTPServer) GetAuditLog(w http.ResponseWriter, r *http.Request, params ports.GetAuditLogParams) {
+	ctx := r.Context()
+
+	if params.From != nil && params.To != nil && params.From.After(*params.To) {
+		httperr.BadRequest(domain.ErrorBadRequestLabel, "from", nil, w, r)
+		return
+	}
+
+	entries, err := s.app.Queries.ListAuditEntries.Handle(ctx, &query.ListAuditEntriesQuery{
+		Filter: mapAuditParamsToFilter(params),
+	})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to list audit entries")
+		return
+	}
+
+	dto.Write(w, r, mapAuditEntriesToResponse(entries))
+}
+
+func mapAuditParamsToFilter(params ports.GetAuditLogParams) domain.AuditFilter {
+	filter := domain.AuditFilter{
+		From: params.From,
+		To:   params.To,
+	}
+	if params.Actor != nil {
+		filter.Actor = *params.Actor
+	}
+	if params.Action != nil {
+		filter.Action = *params.Action
+	}
+	if params.RequestId != nil {
+		filter.RequestID = *params.RequestId
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	if params.Offset != nil {
+		filter.Offset = *params.Offset
+	}
+	return filter
+}
+
+func mapAuditEntriesToResponse(entries []domain.AuditEntry) ports.AuditLogResponse {
+	resp := ports.AuditLogResponse{
+		Entries: make([]ports.AuditEntry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, ports.AuditEntry{
+			Id:           e.ID,
+			Action:       e.Action,
+			Actor:        e.Actor,
+			ClaimedActor: e.ClaimedActor,
+			SourceIp:     e.SourceIP,
+			RequestId:    e.RequestID,
+			Before:       mapSmartPackToPackSizes(e.Before),
+			After:        mapSmartPackToPackSizes(e.After),
+			CreatedAt:    e.CreatedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAuditLog(t *testing.T) {
+	createdAt := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	actor := "ops-dashboard"
+	from := createdAt.Add(time.Hour)
+	to := createdAt
+
+	testCases := []struct {
+		Name         string
+		Params       ports.GetAuditLogParams
+		MockFunc     func(server testHTTPServer)
+		ResponseCode int
+		ResponseBody *ports.AuditLogResponse
+	}{
+		{
+			Name:         "from after to",
+			Params:       ports.GetAuditLogParams{From: &from, To: &to},
+			ResponseCode: http.StatusBadRequest,
+		},
+		{
+			Name: "internal server error",
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedListAuditRepository.(*query.MockAuditRepository).
+					EXPECT().ListAuditEntries(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("internal server error")).
+					AnyTimes()
+			},
+			ResponseCode: http.StatusInternalServerError,
+		},
+		{
+			Name:   "success",
+			Params: ports.GetAuditLogParams{Actor: &actor},
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedListAuditRepository.(*query.MockAuditRepository).
+					EXPECT().ListAuditEntries(gomock.Any(), domain.AuditFilter{Actor: actor, Limit: 100}).
+					Return([]domain.AuditEntry{
+						{
+							ID:        1,
+							Action:    domain.AuditActionSetPackSizes,
+							Actor:     actor,
+							SourceIP:  "10.0.0.12",
+							RequestID: "req-1",
+							Before:    []domain.SmartPack{{Size: 250}},
+							After:     []domain.SmartPack{{Size: 500}, {Size: 1000}},
+							CreatedAt: createdAt,
+						},
+					}, nil).
+					Times(1)
+			},
+			ResponseCode: http.StatusOK,
+			ResponseBody: &ports.AuditLogResponse{
+				Entries: []ports.AuditEntry{
+					{
+						Id:        1,
+						Action:    domain.AuditActionSetPackSizes,
+						Actor:     actor,
+						SourceIp:  "10.0.0.12",
+						RequestId: "req-1",
+						Before:    []ports.PackSize{{Size: 250}},
+						After:     []ports.PackSize{{Size: 500}, {Size: 1000}},
+						CreatedAt: createdAt,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			if tc.MockFunc != nil {
+				tc.MockFunc(testServer)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/api/audit", http.NoBody)
+			r = r.WithContext(context.Background())
+			rw := httptest.NewRecorder()
+
+			testServer.api.GetAuditLog(rw, r, tc.Params)
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+			if tc.ResponseBody != nil {
+				var resp ports.AuditLogResponse
+				require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+				require.Equal(t, *tc.ResponseBody, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server"
+	"github.com/rossi1/smart-pack/pkg/server/dto"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/sirupsen/logrus"
+)
+
+// readinessCheckTimeout bounds each readiness check, so a hanging dependency
+// fails the probe instead of stalling it.
+const readinessCheckTimeout = 2 * time.Second
+
+type readinessCheck struct {
+	name string
+	// failure is reported in place of the error, which only goes to the
+	// logs because anyone can call the probe.
+	failure string
+	run     func(ctx context.Context) error
+}
+
+func (h HTTPServer) LivenessCheck(w http.ResponseWriter, r *http.Request) {
+	dto.Write(w, r, ports.LivenessResponse{Status: "alive"})
+}
+
+// ReadinessCheck runs every check, even after one fails, so the report shows
+// all of them.
+func (h HTTPServer) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-server.ShuttingDown(r.Context()):
+		render.Status(r, http.StatusServiceUnavailable)
+		dto.Write(w, r, ports.ReadinessResponse{Status: ports.ShuttingDown, Checks: []ports.ReadinessCheck{}})
+		return
+	default:
+	}
+
+	resp := ports.ReadinessResponse{
+		Status: ports.Ready,
+		Checks: runReadinessChecks(r.Context(), h.readinessChecks()),
+	}
+	for _, check := range resp.Checks {
+		if check.Status == ports.Fail {
+			resp.Status = ports.NotReady
+			render.Status(r, http.StatusServiceUnavailable)
+			break
+		}
+	}
+	dto.Write(w, r, resp)
+}
+
+func (h HTTPServer) readinessChecks() []readinessCheck {
+	var checks []readinessCheck
+	if h.app.DatabaseHealth != nil {
+		checks = append(checks, readinessCheck{
+			name:    "database",
+			failure: "database is unreachable",
+			run:     h.app.DatabaseHealth.Ping,
+		})
+	}
+	if h.app.SchemaHealth != nil {
+		checks = append(checks, readinessCheck{
+			name:    "schema",
+			failure: "database schema is dirty or outdated",
+			run:     h.app.SchemaHealth.CheckSchema,
+		})
+	}
+	return append(checks, readinessCheck{
+		name:    "pack_sizes",
+		failure: "pack sizes cannot be read or none is enabled",
+		run:     h.checkPackSizes,
+	})
+}
+
+// checkPackSizes fails when calculations would answer
+// error_no_pack_sizes_configured.
+func (h HTTPServer) checkPackSizes(ctx context.Context) error {
+	packs, err := h.app.Queries.GetPackSizes.Handle(ctx, &query.GetPackSizesQuery{})
+	if err != nil {
+		return err
+	}
+	if len(domain.EnabledPackSizes(packs)) == 0 {
+		return domain.ErrNoPackSizes
+	}
+	return nil
+}
+
+func runReadinessChecks(ctx context.Context, checks []readinessCheck) []ports.ReadinessCheck {
+	results := make([]ports.ReadinessCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runReadinessCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func runReadinessCheck(ctx context.Context, check readinessCheck) ports.ReadinessCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	// Buffered, so a check that ignores ctx can still finish after the probe
+	// gave up on it.
+	done := make(chan error, 1)
+	go func() { done <- check.run(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := ports.ReadinessCheck{
+		Name:      check.name,
+		Status:    ports.Pass,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		logrus.WithContext(ctx).WithError(err).WithField("check", check.name).Warn("Readiness check failed")
+		result.Status = ports.Fail
+		result.Error = &check.failure
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/app"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSchemaHealth struct {
+	err error
+}
+
+func (s stubSchemaHealth) CheckSchema(context.Context) error { return s.err }
+
+type failingDatabaseHealth struct {
+	stubDatabaseHealth
+}
+
+func (failingDatabaseHealth) Ping(context.Context) error { return errors.New("connection refused") }
+
+func TestLivenessCheck(t *testing.T) {
+	testServer := newTestAPIServer(t)
+
+	rw := httptest.NewRecorder()
+	testServer.api.LivenessCheck(rw, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.JSONEq(t, `{"status":"alive"}`, rw.Body.String())
+}
+
+func TestReadinessCheck(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		DatabaseHealth app.DatabaseHealth
+		SchemaErr      error
+		PackSizes      []domain.SmartPack
+		PackSizesErr   error
+		ResponseCode   int
+		Status         ports.ReadinessResponseStatus
+		Failed         []string
+	}{
+		{
+			Name:           "ready",
+			DatabaseHealth: stubDatabaseHealth{},
+			PackSizes:      []domain.SmartPack{{Size: 250, Enabled: true}},
+			ResponseCode:   http.StatusOK,
+			Status:         ports.Ready,
+		},
+		{
+			Name:           "database down",
+			DatabaseHealth: failingDatabaseHealth{},
+			PackSizesErr:   errors.New("connection refused"),
+			ResponseCode:   http.StatusServiceUnavailable,
+			Status:         ports.NotReady,
+			Failed:         []string{"database", "pack_sizes"},
+		},
+		{
+			Name:           "schema outdated",
+			DatabaseHealth: stubDatabaseHealth{},
+			SchemaErr:      errors.New("database schema is outdated"),
+			PackSizes:      []domain.SmartPack{{Size: 250, Enabled: true}},
+			ResponseCode:   http.StatusServiceUnavailable,
+			Status:         ports.NotReady,
+			Failed:         []string{"schema"},
+		},
+		{
+			Name:           "no pack size enabled",
+			DatabaseHealth: stubDatabaseHealth{},
+			PackSizes:      []domain.SmartPack{{Size: 250, Enabled: false}},
+			ResponseCode:   http.StatusServiceUnavailable,
+			Status:         ports.NotReady,
+			Failed:         []string{"pack_sizes"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			testServer.api.app.DatabaseHealth = tc.DatabaseHealth
+			testServer.api.app.SchemaHealth = stubSchemaHealth{err: tc.SchemaErr}
+			testServer.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+				EXPECT().GetPackSizes(gomock.Any()).
+				Return(tc.PackSizes, tc.PackSizesErr)
+
+			rw := httptest.NewRecorder()
+			testServer.api.ReadinessCheck(rw, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+			var resp ports.ReadinessResponse
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+			require.Equal(t, tc.Status, resp.Status)
+
+			var names, failed []string
+			for _, check := range resp.Checks {
+				names = append(names, check.Name)
+				require.GreaterOrEqual(t, check.LatencyMs, 0.0)
+				if check.Status == ports.Fail {
+					failed = append(failed, check.Name)
+					require.NotNil(t, check.Error)
+					require.NotContains(t, *check.Error, "connection refused")
+				}
+			}
+			require.Equal(t, []string{"database", "schema", "pack_sizes"}, names)
+			require.Equal(t, tc.Failed, failed)
+		})
+	}
+
+	t.Run("without a database", func(t *testing.T) {
+		testServer := newTestAPIServer(t)
+		testServer.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+			EXPECT().GetPackSizes(gomock.Any()).
+			Return([]domain.SmartPack{{Size: 250, Enabled: true}}, nil)
+
+		rw := httptest.NewRecorder()
+		testServer.api.ReadinessCheck(rw, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		var resp ports.ReadinessResponse
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		require.Len(t, resp.Checks, 1)
+		require.Equal(t, "pack_sizes", resp.Checks[0].Name)
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		testServer := newTestAPIServer(t)
+		testServer.api.app.DatabaseHealth = stubDatabaseHealth{}
+
+		shutdown := make(chan struct{})
+		close(shutdown)
+		r := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+		r = r.WithContext(server.WithShutdown(r.Context(), shutdown))
+		rw := httptest.NewRecorder()
+		testServer.api.ReadinessCheck(rw, r)
+
+		require.Equal(t, http.StatusServiceUnavailable, rw.Code)
+		require.JSONEq(t, `{"status":"shutting_down","checks":[]}`, rw.Body.String())
+	})
+}
@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/packio"
+	"github.com/rossi1/smart-pack/pkg/server/dto"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	importFileField       = "file"
+	maxImportMemoryBytes  = 10 << 20
+	exportDefaultFilename = "pack-sizes"
+)
+
+func (s *HTTPServer) ExportPackSizes(w http.ResponseWriter, r *http.Request, params ports.ExportPackSizesParams) {
+	ctx := r.Context()
+
+	format := packio.FormatJSON
+	if params.Format != nil {
+		f, err := packio.ParseFormat(string(*params.Format))
+		if err != nil {
+			httperr.BadRequest(domain.ErrorBadRequestLabel, "format", err, w, r)
+			return
+		}
+		format = f
+	}
+
+	sizes, err := s.app.Queries.GetPackSizes.Handle(ctx, &query.GetPackSizesQuery{})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to get pack sizes")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := packio.Encode(&buf, format, sizes); err != nil {
+		logrus.WithError(err).Error("Failed to encode pack sizes")
+		httperr.InternalError(domain.ErrorInternalServerErrorLabel, "", err, w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s.%s"`, exportDefaultFilename, format.Extension()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (s *HTTPServer) ImportPackSizes(w http.ResponseWriter, r *http.Request, params ports.ImportPackSizesParams) {
+	ctx := r.Context()
+
+	body, format, err := readImportFile(r, params)
+	if err != nil {
+		httperr.BadRequest(domain.ErrorBadRequestLabel, importFileField, err, w, r)
+		return
+	}
+	defer body.Close()
+
+	decoded, err := packio.Decode(body, format)
+	if err != nil {
+		httperr.BadRequest(domain.ErrorInvalidRequestBodyParameter, importFileField, err, w, r)
+		return
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+	report := newImportReport(decoded, dryRun)
+	if len(decoded.Errors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		dto.Write(w, r, report)
+		return
+	}
+
+	err = s.app.Commands.SetPackSizes.Handle(ctx, &command.SetPackSizesCommand{Sizes: decoded.Packs, DryRun: dryRun})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to import pack sizes")
+		return
+	}
+
+	report.Applied = !dryRun
+	dto.Write(w, r, report)
+}
+
+// readImportFile returns the uploaded file and its format. The format
+// parameter wins over the Content-Type, which wins over the file name.
+func readImportFile(r *http.Request, params ports.ImportPackSizesParams) (io.ReadCloser, packio.Format, error) {
+	var explicit packio.Format
+	if params.Format != nil {
+		f, err := packio.ParseFormat(string(*params.Format))
+		if err != nil {
+			return nil, "", err
+		}
+		explicit = f
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if explicit != "" {
+			return r.Body, explicit, nil
+		}
+		f, err := packio.FormatFromContentType(r.Header.Get("Content-Type"))
+		return r.Body, f, err
+	}
+
+	if err := r.ParseMultipartForm(maxImportMemoryBytes); err != nil {
+		return nil, "", err
+	}
+	file, header, err := r.FormFile(importFileField)
+	if err != nil {
+		return nil, "", err
+	}
+	if explicit != "" {
+		return file, explicit, nil
+	}
+	if f, err := packio.FormatFromContentType(header.Header.Get("Content-Type")); err == nil {
+		return file, f, nil
+	}
+	f, err := packio.FormatFromFilename(header.Filename)
+	if err != nil {
+		_ = file.Close()
+		return nil, "", errors.Join(err, errors.New("set the format parameter"))
+	}
+	return file, f, nil
+}
+
+func newImportReport(decoded packio.Decoded, dryRun bool) ports.ImportReport {
+	report := ports.ImportReport{
+		DryRun:    dryRun,
+		TotalRows: decoded.Rows,
+		Packs:     mapSmartPackToPackSizes(decoded.Packs),
+		Errors:    make([]ports.ImportRowError, 0, len(decoded.Errors)),
+	}
+	for _, e := range decoded.Errors {
+		report.Errors = append(report.Errors, ports.ImportRowError{
+			Row:     e.Row,
+			Field:   emptyToNil(e.Field),
+			Label:   e.Label,
+			Message: e.Message,
+		})
+	}
+	return report
+}
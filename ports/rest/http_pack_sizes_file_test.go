@@ -0,0 +1,187 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportPackSizes(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Format       ports.ExportPackSizesParamsFormat
+		MockFunc     func(server testHTTPServer)
+		ResponseCode int
+		ContentType  string
+		ResponseBody string
+	}{
+		{
+			Name:         "unsupported format",
+			Format:       "xml",
+			ResponseCode: http.StatusBadRequest,
+		},
+		{
+			Name:   "internal server error",
+			Format: ports.ExportPackSizesParamsFormatCsv,
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+					EXPECT().GetPackSizes(gomock.Any()).
+					Return(nil, errors.New("internal server error")).
+					AnyTimes()
+			},
+			ResponseCode: http.StatusInternalServerError,
+		},
+		{
+			Name:   "csv",
+			Format: ports.ExportPackSizesParamsFormatCsv,
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+					EXPECT().GetPackSizes(gomock.Any()).
+					Return([]domain.SmartPack{{Size: 250, Label: "Small box", Enabled: true}, {Size: 500}}, nil).
+					AnyTimes()
+			},
+			ResponseCode: http.StatusOK,
+			ContentType:  "text/csv",
+			ResponseBody: "size,label,sku,gtin,enabled\n250,Small box,,,true\n500,,,,false\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			if tc.MockFunc != nil {
+				tc.MockFunc(testServer)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/api/pack-sizes/export", http.NoBody)
+			r = r.WithContext(context.Background())
+			rw := httptest.NewRecorder()
+
+			testServer.api.ExportPackSizes(rw, r, ports.ExportPackSizesParams{Format: &tc.Format})
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+			if tc.ResponseBody != "" {
+				require.Equal(t, tc.ContentType, rw.Header().Get("Content-Type"))
+				require.Equal(t, tc.ResponseBody, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestImportPackSizes(t *testing.T) {
+	expectSet := func(server testHTTPServer) {
+		repo := server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository)
+		repo.EXPECT().SetPackSizes(gomock.Any(), []domain.SmartPack{
+			{Size: 250, SKU: "BOX-250", Enabled: true},
+			{Size: 500, Enabled: false},
+		}).Return(nil, nil).Times(1)
+		server.deps.mockedAppendAuditRepository.(*command.MockAuditRepository).
+			EXPECT().AppendAuditEntry(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		server.deps.mockedOutboxRepository.(*command.MockOutboxRepository).
+			EXPECT().AppendOutboxEvent(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	}
+	validCSV := "size,sku,enabled\n250,BOX-250,\n500,,false\n"
+
+	testCases := []struct {
+		Name         string
+		ContentType  string
+		Body         func() (*bytes.Buffer, string)
+		DryRun       bool
+		MockFunc     func(server testHTTPServer)
+		ResponseCode int
+		Applied      bool
+		TotalRows    int
+		ErrorRows    []int
+	}{
+		{
+			Name:         "raw csv body",
+			Body:         func() (*bytes.Buffer, string) { return bytes.NewBufferString(validCSV), "text/csv" },
+			MockFunc:     expectSet,
+			ResponseCode: http.StatusOK,
+			Applied:      true,
+			TotalRows:    2,
+		},
+		{
+			Name: "multipart file detected by extension",
+			Body: func() (*bytes.Buffer, string) {
+				var buf bytes.Buffer
+				mw := multipart.NewWriter(&buf)
+				part, _ := mw.CreateFormFile("file", "sizes.csv")
+				_, _ = part.Write([]byte(validCSV))
+				_ = mw.Close()
+				return &buf, mw.FormDataContentType()
+			},
+			MockFunc:     expectSet,
+			ResponseCode: http.StatusOK,
+			Applied:      true,
+			TotalRows:    2,
+		},
+		{
+			Name:         "dry run does not write",
+			Body:         func() (*bytes.Buffer, string) { return bytes.NewBufferString(validCSV), "text/csv" },
+			DryRun:       true,
+			ResponseCode: http.StatusOK,
+			TotalRows:    2,
+		},
+		{
+			Name: "invalid rows are reported",
+			Body: func() (*bytes.Buffer, string) {
+				return bytes.NewBufferString(`{"packs":[{"size":0},{"size":250},{"size":250}]}`), "application/json"
+			},
+			ResponseCode: http.StatusUnprocessableEntity,
+			TotalRows:    3,
+			ErrorRows:    []int{1, 3},
+		},
+		{
+			Name:         "unsupported content type",
+			Body:         func() (*bytes.Buffer, string) { return bytes.NewBufferString(validCSV), "application/octet-stream" },
+			ResponseCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			if tc.MockFunc != nil {
+				tc.MockFunc(testServer)
+			}
+
+			body, contentType := tc.Body()
+			r := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/import", body)
+			r = r.WithContext(context.Background())
+			r.Header.Set("Content-Type", contentType)
+			rw := httptest.NewRecorder()
+
+			testServer.api.ImportPackSizes(rw, r, ports.ImportPackSizesParams{DryRun: &tc.DryRun})
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+			if tc.ResponseCode == http.StatusBadRequest {
+				return
+			}
+
+			var report ports.ImportReport
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &report))
+			require.Equal(t, tc.Applied, report.Applied)
+			require.Equal(t, tc.DryRun, report.DryRun)
+			require.Equal(t, tc.TotalRows, report.TotalRows)
+
+			rows := make([]int, 0, len(report.Errors))
+			for _, e := range report.Errors {
+				rows = append(rows, e.Row)
+			}
+			require.ElementsMatch(t, tc.ErrorRows, rows)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	packSizesEventName     = "pack_sizes"
+	defaultStreamHeartbeat = 15 * time.Second
+	streamHeartbeatComment = ": heartbeat\n\n"
+)
+
+var errEventStreamNotAccepted = errors.New("request does not accept text/event-stream")
+
+// StreamPackSizes sends the current pack sizes as a Server-Sent Event and
+// then one event per committed change, until the client disconnects or the
+// server shuts down.
+func (s *HTTPServer) StreamPackSizes(w http.ResponseWriter, r *http.Request, params ports.StreamPackSizesParams) {
+	ctx := r.Context()
+
+	if !server.AcceptsEventStream(r) {
+		httperr.WithStatus(domain.ErrorNotAcceptableLabel, "", errEventStreamNotAccepted, w, r, http.StatusNotAcceptable)
+		return
+	}
+
+	// Subscribe before the first read, so a change committed in between is
+	// not missed.
+	changes, unsubscribe := s.app.PackSizesChanges.Subscribe()
+	defer unsubscribe()
+
+	current, err := s.app.Queries.GetVersionedPackSizes.Handle(ctx, &query.GetVersionedPackSizesQuery{})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to get pack sizes for stream")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server write timeout would cut the stream off; it ends on its own
+	// terms instead.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", server.EventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if params.LastEventID == nil || *params.LastEventID != strconv.FormatInt(current.Version, 10) {
+		if err := writePackSizesEvent(w, current); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(s.streamHeartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-server.ShuttingDown(ctx):
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, streamHeartbeatComment); err != nil {
+				return
+			}
+		case <-changes:
+			next, err := s.app.Queries.GetVersionedPackSizes.Handle(ctx, &query.GetVersionedPackSizesQuery{})
+			if err != nil {
+				// The client keeps the last set it saw; the next change or a
+				// reconnect catches it up.
+				logrus.WithContext(ctx).WithError(err).Error("Failed to get pack sizes for stream")
+				continue
+			}
+			if next.Version == current.Version {
+				continue
+			}
+			current = next
+			if err := writePackSizesEvent(w, current); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *HTTPServer) streamHeartbeat() time.Duration {
+	if s.app.AppConfig == nil || s.app.AppConfig.PackSizesStreamHeartbeat <= 0 {
+		return defaultStreamHeartbeat
+	}
+	return s.app.AppConfig.PackSizesStreamHeartbeat
+}
+
+func writePackSizesEvent(w io.Writer, sizes domain.VersionedPackSizes) error {
+	data, err := json.Marshal(ports.PackSizesEvent{
+		Version:   sizes.Version,
+		PackSizes: domain.EnabledPackSizes(sizes.Packs),
+		Packs:     mapSmartPackToPackSizes(sizes.Packs),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", sizes.Version, packSizesEventName, data)
+	return err
+}
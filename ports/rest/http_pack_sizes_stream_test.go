@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/app/query"
+	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamPackSizes(t *testing.T) {
+	v1 := domain.VersionedPackSizes{Version: 1, Packs: []domain.SmartPack{{Size: 250, Enabled: true}}}
+	v2 := domain.VersionedPackSizes{Version: 2, Packs: []domain.SmartPack{
+		{Size: 500, Enabled: true},
+		{Size: 250, Enabled: false},
+	}}
+
+	t.Run("not acceptable", func(t *testing.T) {
+		testServer := newTestAPIServer(t)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/pack-sizes/stream", nil)
+		testServer.api.StreamPackSizes(rw, req, ports.StreamPackSizesParams{})
+
+		require.Equal(t, http.StatusNotAcceptable, rw.Code)
+	})
+
+	t.Run("sends the current set and every change", func(t *testing.T) {
+		testServer := newTestAPIServer(t)
+		repo := testServer.deps.mockedVersionedPackSizesRepo.(*query.MockGetVersionedPackSizesRepository)
+		gomock.InOrder(
+			repo.EXPECT().GetVersionedPackSizes(gomock.Any()).Return(v1, nil),
+			repo.EXPECT().GetVersionedPackSizes(gomock.Any()).Return(v2, nil),
+		)
+
+		events := openPackSizesStream(t, testServer, "")
+
+		event := events.next(t)
+		require.Equal(t, "1", event.ID)
+		require.Equal(t, "pack_sizes", event.Name)
+		require.JSONEq(t, `{"version":1,"pack_sizes":[250],"packs":[{"size":250,"enabled":true}]}`, event.Data)
+
+		testServer.deps.packSizesNotifier.Notify()
+
+		event = events.next(t)
+		require.Equal(t, "2", event.ID)
+		require.Equal(t, "pack_sizes", event.Name)
+		require.JSONEq(t,
+			`{"version":2,"pack_sizes":[500],"packs":[{"size":500,"enabled":true},{"size":250,"enabled":false}]}`,
+			event.Data)
+	})
+
+	t.Run("resumes from Last-Event-ID", func(t *testing.T) {
+		testServer := newTestAPIServer(t)
+		repo := testServer.deps.mockedVersionedPackSizesRepo.(*query.MockGetVersionedPackSizesRepository)
+		gomock.InOrder(
+			repo.EXPECT().GetVersionedPackSizes(gomock.Any()).Return(v1, nil),
+			// A notification without a new version sends nothing.
+			repo.EXPECT().GetVersionedPackSizes(gomock.Any()).Return(v1, nil),
+			repo.EXPECT().GetVersionedPackSizes(gomock.Any()).Return(v2, nil),
+		)
+
+		events := openPackSizesStream(t, testServer, "1")
+
+		testServer.deps.packSizesNotifier.Notify()
+		// Notifications coalesce; give the handler time to consume the
+		// first one so the second is not folded into it.
+		time.Sleep(50 * time.Millisecond)
+		testServer.deps.packSizesNotifier.Notify()
+
+		require.Equal(t, "2", events.next(t).ID)
+	})
+
+	t.Run("sends heartbeats", func(t *testing.T) {
+		testServer := newTestAPIServer(t)
+		testServer.api.app.AppConfig = &appConfig.AppConfig{PackSizesStreamHeartbeat: 10 * time.Millisecond}
+		testServer.deps.mockedVersionedPackSizesRepo.(*query.MockGetVersionedPackSizesRepository).
+			EXPECT().GetVersionedPackSizes(gomock.Any()).Return(v1, nil)
+
+		events := openPackSizesStream(t, testServer, "1")
+
+		require.Equal(t, sseEvent{Comment: "heartbeat"}, events.next(t))
+	})
+}
+
+type sseEvent struct {
+	ID      string
+	Name    string
+	Data    string
+	Comment string
+}
+
+type sseReader struct {
+	lines *bufio.Scanner
+}
+
+func openPackSizesStream(t *testing.T, testServer testHTTPServer, lastEventID string) *sseReader {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params ports.StreamPackSizesParams
+		if id := r.Header.Get("Last-Event-ID"); id != "" {
+			params.LastEventID = &id
+		}
+		testServer.api.StreamPackSizes(w, r, params)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	return &sseReader{lines: bufio.NewScanner(resp.Body)}
+}
+
+// next reads up to the blank line that ends an event or comment.
+func (r *sseReader) next(t *testing.T) sseEvent {
+	t.Helper()
+
+	var event sseEvent
+	for r.lines.Scan() {
+		line := r.lines.Text()
+		if line == "" {
+			return event
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "":
+			event.Comment = value
+		case "id":
+			event.ID = value
+		case "event":
+			event.Name = value
+		case "data":
+			event.Data = value
+		}
+	}
+	require.NoError(t, r.lines.Err())
+	t.Fatal("stream ended")
+	return event
+}
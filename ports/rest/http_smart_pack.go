@@ -16,16 +16,21 @@ import (
 )
 
 func (h *SetPackSizesRequestValidation) valid() *httperr.ErrorMessageBody {
+	if len(h.PackSizes) == 0 && len(h.Packs) == 0 {
+		return httperr.NewErrorMessageBodyWithMessages(
+			httperr.NewErrorMessage(domain.ErrorInvalidRequestBodyParameter, "pack_sizes"))
+	}
+
 	validate := validator.New()
 	validation := validate.Struct(h)
 	if validation != nil {
 		validationErrors := validator.ValidationErrors{}
 		_ = errors.As(validation, &validationErrors)
-		return httperr.NewErrorMessageBodyWithMessages(
-			httperr.NewErrorMessage(
-				domain.ErrorInvalidRequestBodyParameter,
-				validationErrors[0].Field(),
-			))
+		messages := make([]httperr.Message, 0, len(validationErrors))
+		for _, fieldErr := range validationErrors {
+			messages = append(messages, httperr.NewErrorMessage(domain.ErrorInvalidRequestBodyParameter, fieldErr.Field())...)
+		}
+		return httperr.NewErrorMessageBodyWithMessages(messages)
 	}
 	return nil
 }
@@ -39,34 +44,9 @@ func (s *HTTPServer) CalculatePacks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.ItemsOrdered <= 0 {
-		httperr.BadRequest(domain.ErrorBadRequestLabel, "items_ordered must be positive", nil, w, r)
-		return
-	}
-
-	packSizes, err := s.app.Queries.GetPackSizes.Handle(ctx, &query.GetPackSizesQuery{})
-
-	if v, ok := domain.IsHTTPCustomError(err); ok {
-		httperr.WithStatus(v.Label(), "", err, w, r, v.Status())
-		return
-	}
-
-	if err != nil {
-		logrus.WithError(err).Error("Failed to get pack sizes")
-		httperr.InternalError(domain.ErrorInternalServerErrorLabel, "", err, w, r)
-		return
-	}
-
-	packSizesInt := make([]int, len(packSizes))
-	for i, size := range packSizes {
-		packSizesInt[i] = size.Size
-	}
-
-	result, err := s.app.PackCalculator.Calculate(req.ItemsOrdered, packSizesInt)
-
+	result, err := s.app.Queries.CalculatePacks.Handle(ctx, &query.CalculatePacksQuery{ItemsOrdered: req.ItemsOrdered})
 	if err != nil {
-		logrus.WithError(err).Error("Failed to calculate packs")
-		httperr.InternalError(domain.ErrorInternalServerErrorLabel, "", err, w, r)
+		respondWithError(w, r, err, "Failed to calculate packs")
 		return
 	}
 
@@ -77,15 +57,8 @@ func (s *HTTPServer) CalculatePacks(w http.ResponseWriter, r *http.Request) {
 func (s *HTTPServer) GetPackSizes(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	sizes, err := s.app.Queries.GetPackSizes.Handle(ctx, &query.GetPackSizesQuery{})
-	if v, ok := domain.IsHTTPCustomError(err); ok {
-		logrus.WithError(err).Error("Failed to get pack sizes")
-		httperr.WithStatus(v.Label(), "", err, w, r, v.Status())
-		return
-	}
-
 	if err != nil {
-		logrus.WithError(err).Error("Failed to get pack sizes")
-		httperr.InternalError(domain.ErrorInternalServerErrorLabel, "", err, w, r)
+		respondWithError(w, r, err, "Failed to get pack sizes")
 		return
 	}
 
@@ -104,16 +77,16 @@ func (s *HTTPServer) SetPackSizes(w http.ResponseWriter, r *http.Request) {
 	}
 	if validationErr := req.valid(); validationErr != nil {
 		logrus.WithContext(ctx).Error(validationErr)
-		httperr.BadRequest(validationErr.Messages[0].Label, validationErr.Messages[0].FormProperty, nil, w, r)
+		httperr.WithMessages(validationErr.Messages, nil, w, r, http.StatusBadRequest)
 		return
 	}
 
 	cmd := command.SetPackSizesCommand{
 		Sizes: mapToSmartPack(req),
 	}
-	if err := s.app.Commands.SetPackSizes.Handle(ctx, &cmd); err != nil {
-		logrus.WithError(err).Error("Failed to set pack sizes")
-		httperr.InternalError(domain.ErrorInternalServerErrorLabel, "", err, w, r)
+	err := s.app.Commands.SetPackSizes.Handle(ctx, &cmd)
+	if err != nil {
+		respondWithError(w, r, err, "Failed to set pack sizes")
 		return
 	}
 
@@ -121,21 +94,46 @@ func (s *HTTPServer) SetPackSizes(w http.ResponseWriter, r *http.Request) {
 }
 
 func mapToSmartPack(req SetPackSizesRequestValidation) []domain.SmartPack {
+	if len(req.Packs) > 0 {
+		sizes := make([]domain.SmartPack, 0, len(req.Packs))
+		for _, p := range req.Packs {
+			sizes = append(sizes, domain.SmartPack{
+				Size:    p.Size,
+				Label:   valueOrEmpty(p.Label),
+				SKU:     valueOrEmpty(p.Sku),
+				GTIN:    valueOrEmpty(p.Gtin),
+				Enabled: p.Enabled == nil || *p.Enabled,
+			})
+		}
+		return sizes
+	}
+
 	sizes := make([]domain.SmartPack, 0, len(req.PackSizes))
 	for _, size := range req.PackSizes {
-		sizes = append(sizes, domain.SmartPack{Size: size})
+		sizes = append(sizes, domain.SmartPack{Size: size, Enabled: true})
 	}
 	return sizes
 }
 
 func mapSmartPackToPackSizesResponse(smartPack []domain.SmartPack) ports.PackSizesResponse {
-	sizes := make([]int, len(smartPack))
-	for i, size := range smartPack {
-		sizes[i] = size.Size
-	}
 	return ports.PackSizesResponse{
-		PackSizes: sizes,
+		PackSizes: domain.EnabledPackSizes(smartPack),
+		Packs:     mapSmartPackToPackSizes(smartPack),
+	}
+}
+
+func mapSmartPackToPackSizes(packs []domain.SmartPack) []ports.PackSize {
+	sizes := make([]ports.PackSize, 0, len(packs))
+	for _, p := range packs {
+		sizes = append(sizes, ports.PackSize{
+			Size:    p.Size,
+			Label:   emptyToNil(p.Label),
+			Sku:     emptyToNil(p.SKU),
+			Gtin:    emptyToNil(p.GTIN),
+			Enabled: p.Enabled,
+		})
 	}
+	return sizes
 }
 
 func mapDomainToPortsPackSolution(result *domain.PackSolution) ports.PackSolution {
@@ -155,8 +153,25 @@ func mapDomainToPortsPackSolution(result *domain.PackSolution) ports.PackSolutio
 		resp.PackDetails = append(resp.PackDetails, ports.PackDetail{
 			Size:     d.Size,
 			Quantity: d.Quantity,
+			Label:    emptyToNil(d.Label),
+			Sku:      emptyToNil(d.SKU),
+			Gtin:     emptyToNil(d.GTIN),
 		})
 	}
 
 	return resp
 }
+
+func emptyToNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -48,6 +48,32 @@ func TestCalculatePacks(t *testing.T) {
 			},
 			ResponseCode: http.StatusInternalServerError,
 		},
+		{
+			Name: "no pack sizes configured",
+			RequestBody: ports.CalculateRequest{
+				ItemsOrdered: 100,
+			},
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+					EXPECT().
+					GetPackSizes(gomock.Any()).
+					Return([]domain.SmartPack{{Size: 250, Enabled: false}}, nil).
+					AnyTimes()
+				server.deps.mockedPackCalculator.(*smart_calculator.MockPackCalculator).
+					EXPECT().
+					Calculate(gomock.Any(), 100, []int{}).
+					Return(nil, domain.ErrNoPackSizes).
+					AnyTimes()
+			},
+			ResponseCode: http.StatusNotFound,
+		},
+		{
+			Name: "order above the limit",
+			RequestBody: ports.CalculateRequest{
+				ItemsOrdered: domain.DefaultInputLimits.MaxItemsOrdered + 1,
+			},
+			ResponseCode: http.StatusUnprocessableEntity,
+		},
 		{
 			Name: "success",
 			RequestBody: ports.CalculateRequest{
@@ -59,7 +85,8 @@ func TestCalculatePacks(t *testing.T) {
 					EXPECT().
 					GetPackSizes(gomock.Any()).
 					Return([]domain.SmartPack{
-						{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000},
+						{Size: 250, Enabled: true}, {Size: 500, Enabled: true}, {Size: 1000, Enabled: true},
+						{Size: 2000, Enabled: true}, {Size: 5000, Enabled: true},
 					}, nil).
 					AnyTimes()
 
@@ -76,7 +103,7 @@ func TestCalculatePacks(t *testing.T) {
 				}
 				server.deps.mockedPackCalculator.(*smart_calculator.MockPackCalculator).
 					EXPECT().
-					Calculate(gomock.Any(), gomock.Any()).
+					Calculate(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(expectedSolution, nil).
 					AnyTimes()
 			},
@@ -92,6 +119,44 @@ func TestCalculatePacks(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "disabled packs are excluded and metadata is attached",
+			RequestBody: ports.CalculateRequest{
+				ItemsOrdered: 300,
+			},
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+					EXPECT().
+					GetPackSizes(gomock.Any()).
+					Return([]domain.SmartPack{
+						{Size: 500, Enabled: false},
+						{Size: 250, Label: "Small box", SKU: "BOX-250", Enabled: true},
+					}, nil).
+					AnyTimes()
+
+				server.deps.mockedPackCalculator.(*smart_calculator.MockPackCalculator).
+					EXPECT().
+					Calculate(gomock.Any(), 300, []int{250}).
+					Return(&domain.PackSolution{
+						ItemsOrdered: 300,
+						TotalItems:   500,
+						TotalPacks:   2,
+						Packs:        map[int]int{250: 2},
+						PackDetails:  []domain.PackDetail{{Size: 250, Quantity: 2}},
+					}, nil).
+					Times(1)
+			},
+			ResponseCode: http.StatusOK,
+			ResponseBody: &ports.PackSolution{
+				ItemsOrdered: 300,
+				TotalItems:   500,
+				TotalPacks:   2,
+				Packs:        map[string]int{"250": 2},
+				PackDetails: []ports.PackDetail{
+					{Size: 250, Quantity: 2, Label: stringPtr("Small box"), Sku: stringPtr("BOX-250")},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -157,12 +222,21 @@ func TestGetPackSizes(t *testing.T) {
 			MockFunc: func(server testHTTPServer) {
 				server.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
 					EXPECT().GetPackSizes(gomock.Any()).
-					Return([]domain.SmartPack{{Size: 250}, {Size: 500}, {Size: 1000}, {Size: 2000}, {Size: 5000}}, nil).
+					Return([]domain.SmartPack{
+						{Size: 250, Label: "Small box", SKU: "BOX-250", GTIN: "4006381333931", Enabled: true},
+						{Size: 500, Enabled: false},
+						{Size: 1000, Enabled: true},
+					}, nil).
 					AnyTimes()
 			},
 			ResponseCode: http.StatusOK,
 			ResponseBody: ports.PackSizesResponse{
-				PackSizes: []int{250, 500, 1000, 2000, 5000},
+				PackSizes: []int{250, 1000},
+				Packs: []ports.PackSize{
+					{Size: 250, Label: stringPtr("Small box"), Sku: stringPtr("BOX-250"), Gtin: stringPtr("4006381333931"), Enabled: true},
+					{Size: 500, Enabled: false},
+					{Size: 1000, Enabled: true},
+				},
 			},
 		},
 	}
@@ -201,21 +275,125 @@ func TestSetPackSizes(t *testing.T) {
 		{
 			Name: "internal server error",
 			MockFunc: func(server testHTTPServer) {
-				server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository).
-					EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+				repo := server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository)
+				repo.EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("internal server error")).
+					AnyTimes()
+			},
+			ResponseCode: http.StatusInternalServerError,
+			RequestBody: ports.SetPackSizesRequest{
+				PackSizes: []int{250},
+			},
+		},
+
+		{
+			Name:         "missing pack sizes",
+			ResponseCode: http.StatusBadRequest,
+		},
+
+		{
+			Name:         "invalid pack size",
+			ResponseCode: http.StatusBadRequest,
+			RequestBody: ports.SetPackSizesRequest{
+				PackSizes: []int{0, 250},
+			},
+		},
+
+		{
+			Name:         "duplicate pack size",
+			ResponseCode: http.StatusBadRequest,
+			RequestBody: ports.SetPackSizesRequest{
+				PackSizes: []int{250, 250},
+			},
+		},
+
+		{
+			Name:         "invalid gtin",
+			ResponseCode: http.StatusBadRequest,
+			RequestBody: ports.SetPackSizesRequest{
+				Packs: []ports.PackSizeInput{{Size: 250, Gtin: stringPtr("4006381333932")}},
+			},
+		},
+
+		{
+			Name: "read-only pack sizes",
+			MockFunc: func(server testHTTPServer) {
+				repo := server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository)
+				repo.EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrPackSizesReadOnly).
+					AnyTimes()
+			},
+			ResponseCode: http.StatusConflict,
+			RequestBody: ports.SetPackSizesRequest{
+				PackSizes: []int{500},
+			},
+		},
+
+		{
+			Name: "audit append error",
+			MockFunc: func(server testHTTPServer) {
+				repo := server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository)
+				repo.EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+					Return([]domain.SmartPack{{Size: 250}}, nil).
+					AnyTimes()
+				server.deps.mockedAppendAuditRepository.(*command.MockAuditRepository).
+					EXPECT().AppendAuditEntry(gomock.Any(), gomock.Any()).
 					Return(errors.New("internal server error")).
 					AnyTimes()
 			},
 			ResponseCode: http.StatusInternalServerError,
+			RequestBody: ports.SetPackSizesRequest{
+				PackSizes: []int{500},
+			},
 		},
 
 		{
-			Name: "success",
+			Name: "outbox append error",
 			MockFunc: func(server testHTTPServer) {
-				server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository).
-					EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+				repo := server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository)
+				repo.EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+					Return([]domain.SmartPack{{Size: 250}}, nil).
+					AnyTimes()
+				server.deps.mockedAppendAuditRepository.(*command.MockAuditRepository).
+					EXPECT().AppendAuditEntry(gomock.Any(), gomock.Any()).
 					Return(nil).
 					AnyTimes()
+				server.deps.mockedOutboxRepository.(*command.MockOutboxRepository).
+					EXPECT().AppendOutboxEvent(gomock.Any(), gomock.Any()).
+					Return(errors.New("internal server error")).
+					AnyTimes()
+			},
+			ResponseCode: http.StatusInternalServerError,
+			RequestBody: ports.SetPackSizesRequest{
+				PackSizes: []int{500},
+			},
+		},
+
+		{
+			Name: "success",
+			MockFunc: func(server testHTTPServer) {
+				repo := server.deps.mockedSetPackSizesRepository.(*command.MockSetPackSizesRepository)
+				repo.EXPECT().SetPackSizes(gomock.Any(), gomock.Any()).
+					Return([]domain.SmartPack{{Size: 250}}, nil).
+					AnyTimes()
+				server.deps.mockedAppendAuditRepository.(*command.MockAuditRepository).
+					EXPECT().AppendAuditEntry(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, entry domain.AuditEntry) error {
+						if entry.Action != domain.AuditActionSetPackSizes || len(entry.Before) != 1 || len(entry.After) != 5 {
+							return errors.New("unexpected audit entry")
+						}
+						return nil
+					}).
+					AnyTimes()
+				server.deps.mockedOutboxRepository.(*command.MockOutboxRepository).
+					EXPECT().AppendOutboxEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, event domain.OutboxEvent) error {
+						if event.Type != domain.EventPackSizesChanged {
+							return errors.New("unexpected outbox event")
+						}
+						return nil
+					}).
+					Times(1)
 			},
 			ResponseCode: http.StatusOK,
 			RequestBody: ports.SetPackSizesRequest{
@@ -245,3 +423,40 @@ func TestSetPackSizes(t *testing.T) {
 		})
 	}
 }
+
+func TestSetPackSizesProblemDetails(t *testing.T) {
+	testServer := newTestAPIServer(t)
+
+	data, err := json.Marshal(ports.SetPackSizesRequest{
+		Packs: []ports.PackSizeInput{
+			{Size: 0, Sku: stringPtr("box 1")},
+			{Size: 250},
+			{Size: 250},
+		},
+	})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/api/pack-sizes", bytes.NewReader(data))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/problem+json")
+	rw := httptest.NewRecorder()
+
+	testServer.api.SetPackSizes(rw, r)
+
+	require.Equal(t, http.StatusBadRequest, rw.Code)
+	require.Equal(t, "application/problem+json", rw.Header().Get("Content-Type"))
+
+	var problem ports.Problem
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &problem))
+	require.Equal(t, "urn:smart-pack:error_invalid_pack_size", problem.Type)
+	require.Equal(t, http.StatusBadRequest, problem.Status)
+	require.Equal(t, []ports.ProblemError{
+		{Field: stringPtr("packs[0].size"), Label: domain.ErrorInvalidPackSizeLabel},
+		{Field: stringPtr("packs[0].sku"), Label: domain.ErrorInvalidPackSKULabel},
+		{Field: stringPtr("packs[2].size"), Label: domain.ErrorDuplicatePackSizeLabel},
+	}, problem.Errors)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -1,7 +1,18 @@
 package rest
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/stretchr/testify/require"
 )
 
 type testHTTPServer struct {
@@ -17,3 +28,50 @@ func newTestAPIServer(t *testing.T) testHTTPServer {
 		deps: deps,
 	}
 }
+
+type stubDatabaseHealth struct {
+	stats domain.DatabaseStats
+}
+
+func (s stubDatabaseHealth) Ping(context.Context) error { return nil }
+
+func (s stubDatabaseHealth) Stats() domain.DatabaseStats { return s.stats }
+
+func TestHealthCheck(t *testing.T) {
+	testServer := newTestAPIServer(t)
+	testServer.api.app.DatabaseHealth = stubDatabaseHealth{stats: domain.DatabaseStats{
+		MaxConns:        10,
+		TotalConns:      3,
+		IdleConns:       2,
+		AcquiredConns:   1,
+		AcquireCount:    42,
+		AcquireDuration: 1500 * time.Millisecond,
+	}}
+
+	rw := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/health", nil)
+	testServer.api.HealthCheck(rw, r)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+
+	var resp ports.HealthResponse
+	require.NoError(t, json.NewDecoder(rw.Body).Decode(&resp))
+	require.Equal(t, "healthy", resp.Status)
+	require.NotNil(t, resp.Database)
+	require.Equal(t, int32(10), resp.Database.MaxConns)
+	require.Equal(t, int32(1), resp.Database.AcquiredConns)
+	require.Equal(t, int64(1500), resp.Database.AcquireDurationMs)
+}
+
+func TestHandlerRejectsInvalidParameters(t *testing.T) {
+	testServer := newTestAPIServer(t)
+	handler := Handler(testServer.api.app, chi.NewRouter(), HandlerOptions{})
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodDelete, "/webhooks/abc", nil))
+
+	require.Equal(t, http.StatusBadRequest, rw.Code)
+	var body httperr.ErrorMessageBody
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+	require.Equal(t, httperr.NewErrorMessage(domain.ErrorInvalidRequestParameterLabel, "id"), body.Messages)
+}
@@ -0,0 +1,149 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server/dto"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/sirupsen/logrus"
+)
+
+func (s *HTTPServer) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.app.Queries.ListWebhooks.Handle(r.Context(), &query.ListWebhooksQuery{})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to list webhooks")
+		return
+	}
+
+	resp := ports.WebhookListResponse{Webhooks: make([]ports.Webhook, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Webhooks = append(resp.Webhooks, ports.Webhook{
+			Id:         sub.ID,
+			Url:        sub.URL,
+			EventTypes: mapWebhookEventTypes(sub.EventTypes),
+			CreatedAt:  sub.CreatedAt,
+		})
+	}
+	dto.Write(w, r, resp)
+}
+
+func (s *HTTPServer) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	var req ports.RegisterWebhookRequest
+	if err := dto.Read(r, &req); err != nil {
+		httperr.UnprocessableEntity(domain.ErrorUnprocessableEntityLabel, "", err, w, r)
+		return
+	}
+
+	cmd := command.RegisterWebhookCommand{
+		URL:    req.Url,
+		Secret: valueOrEmpty(req.Secret),
+	}
+	for _, t := range req.EventTypes {
+		cmd.EventTypes = append(cmd.EventTypes, string(t))
+	}
+	err := s.app.Commands.RegisterWebhook.Handle(r.Context(), &cmd)
+	if err != nil {
+		respondWithError(w, r, err, "Failed to register webhook")
+		return
+	}
+
+	sub := cmd.Subscription
+	render.Status(r, http.StatusCreated)
+	dto.Write(w, r, ports.RegisteredWebhook{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: mapWebhookEventTypes(sub.EventTypes),
+		CreatedAt:  sub.CreatedAt,
+		Secret:     sub.Secret,
+	})
+}
+
+func (s *HTTPServer) DeleteWebhook(w http.ResponseWriter, r *http.Request, id int64) {
+	err := s.app.Commands.DeleteWebhook.Handle(r.Context(), &command.DeleteWebhookCommand{ID: id})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to delete webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *HTTPServer) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request, id int64, params ports.ListWebhookDeliveriesParams) {
+	filter := domain.WebhookDeliveryFilter{SubscriptionID: id}
+	if params.Status != nil {
+		filter.Status = string(*params.Status)
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	if params.Offset != nil {
+		filter.Offset = *params.Offset
+	}
+
+	deliveries, err := s.app.Queries.ListWebhookDeliveries.Handle(r.Context(), &query.ListWebhookDeliveriesQuery{Filter: filter})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to list webhook deliveries")
+		return
+	}
+
+	resp := ports.WebhookDeliveriesResponse{Deliveries: make([]ports.WebhookDelivery, 0, len(deliveries))}
+	for _, d := range deliveries {
+		delivery, err := mapWebhookDelivery(d)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to decode webhook delivery payload")
+			httperr.InternalError(domain.ErrorInternalServerErrorLabel, "", err, w, r)
+			return
+		}
+		resp.Deliveries = append(resp.Deliveries, delivery)
+	}
+	dto.Write(w, r, resp)
+}
+
+func (s *HTTPServer) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request, id int64) {
+	err := s.app.Commands.ReplayWebhookDelivery.Handle(r.Context(), &command.ReplayWebhookDeliveryCommand{ID: id})
+	if err != nil {
+		respondWithError(w, r, err, "Failed to replay webhook delivery")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func mapWebhookEventTypes(types []string) []ports.WebhookEventType {
+	mapped := make([]ports.WebhookEventType, 0, len(types))
+	for _, t := range types {
+		mapped = append(mapped, ports.WebhookEventType(t))
+	}
+	return mapped
+}
+
+func mapWebhookDelivery(d domain.WebhookDelivery) (ports.WebhookDelivery, error) {
+	var payload ports.WebhookEvent
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		return ports.WebhookDelivery{}, err
+	}
+
+	delivery := ports.WebhookDelivery{
+		Id:            d.ID,
+		WebhookId:     d.SubscriptionID,
+		EventId:       d.EventID,
+		EventType:     ports.WebhookEventType(d.EventType),
+		Status:        ports.WebhookDeliveryStatus(d.Status),
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     emptyToNil(d.LastError),
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+		Payload:       payload,
+	}
+	if d.ResponseStatus != 0 {
+		delivery.ResponseStatus = &d.ResponseStatus
+	}
+	return delivery, nil
+}
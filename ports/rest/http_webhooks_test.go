@@ -0,0 +1,249 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterWebhook(t *testing.T) {
+	createdAt := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Name         string
+		RequestBody  ports.RegisterWebhookRequest
+		MockFunc     func(server testHTTPServer)
+		ResponseCode int
+	}{
+		{
+			Name: "invalid url",
+			RequestBody: ports.RegisterWebhookRequest{
+				Url:        "ftp://partner.example.com",
+				EventTypes: []ports.WebhookEventType{ports.PackSizesChanged},
+			},
+			ResponseCode: http.StatusBadRequest,
+		},
+		{
+			Name: "unknown event type",
+			RequestBody: ports.RegisterWebhookRequest{
+				Url:        "https://partner.example.com/hooks",
+				EventTypes: []ports.WebhookEventType{"order.created"},
+			},
+			ResponseCode: http.StatusBadRequest,
+		},
+		{
+			Name: "internal server error",
+			RequestBody: ports.RegisterWebhookRequest{
+				Url:        "https://partner.example.com/hooks",
+				EventTypes: []ports.WebhookEventType{ports.PackSizesChanged},
+			},
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedRegisterWebhookRepo.(*command.MockRegisterWebhookRepository).
+					EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					Return(domain.WebhookSubscription{}, errors.New("internal server error")).
+					Times(1)
+			},
+			ResponseCode: http.StatusInternalServerError,
+		},
+		{
+			Name: "success",
+			RequestBody: ports.RegisterWebhookRequest{
+				Url:        "https://partner.example.com/hooks",
+				EventTypes: []ports.WebhookEventType{ports.PackSizesChanged},
+			},
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedRegisterWebhookRepo.(*command.MockRegisterWebhookRepository).
+					EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+						sub.ID = 3
+						sub.CreatedAt = createdAt
+						return sub, nil
+					}).
+					Times(1)
+			},
+			ResponseCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			if tc.MockFunc != nil {
+				tc.MockFunc(testServer)
+			}
+
+			data, err := json.Marshal(&tc.RequestBody)
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/webhooks", bytes.NewReader(data))
+			r = r.WithContext(context.Background())
+			r.Header.Set("content-type", "application/json")
+			rw := httptest.NewRecorder()
+
+			testServer.api.RegisterWebhook(rw, r)
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+			if rw.Code == http.StatusCreated {
+				var resp ports.RegisteredWebhook
+				require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+				require.Equal(t, int64(3), resp.Id)
+				require.Equal(t, createdAt, resp.CreatedAt)
+				require.Regexp(t, `^whsec_[0-9a-f]+$`, resp.Secret, "a secret is generated when omitted")
+			}
+		})
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Err          error
+		ResponseCode int
+	}{
+		{Name: "not found", Err: domain.ErrWebhookNotFound, ResponseCode: http.StatusNotFound},
+		{Name: "internal server error", Err: errors.New("internal server error"), ResponseCode: http.StatusInternalServerError},
+		{Name: "success", ResponseCode: http.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			testServer.deps.mockedDeleteWebhookRepo.(*command.MockDeleteWebhookRepository).
+				EXPECT().DeleteWebhookSubscription(gomock.Any(), int64(3)).
+				Return(tc.Err).
+				Times(1)
+
+			r := httptest.NewRequest(http.MethodDelete, "/api/webhooks/3", http.NoBody)
+			rw := httptest.NewRecorder()
+
+			testServer.api.DeleteWebhook(rw, r, 3)
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+		})
+	}
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	createdAt := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	status := ports.ListWebhookDeliveriesParamsStatusFailed
+
+	testCases := []struct {
+		Name         string
+		MockFunc     func(server testHTTPServer)
+		ResponseCode int
+		Deliveries   int
+	}{
+		{
+			Name: "unknown webhook",
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedListWebhooksRepo.(*query.MockWebhookRepository).
+					EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrWebhookNotFound).
+					Times(1)
+			},
+			ResponseCode: http.StatusNotFound,
+		},
+		{
+			Name: "success",
+			MockFunc: func(server testHTTPServer) {
+				server.deps.mockedListWebhooksRepo.(*query.MockWebhookRepository).
+					EXPECT().ListWebhookDeliveries(gomock.Any(), domain.WebhookDeliveryFilter{
+					SubscriptionID: 3,
+					Status:         domain.WebhookDeliveryFailed,
+					Limit:          100,
+				}).
+					Return([]domain.WebhookDelivery{{
+						ID:             8,
+						SubscriptionID: 3,
+						EventID:        "evt_1",
+						EventType:      domain.EventPackSizesChanged,
+						Payload:        []byte(`{"id":"evt_1","type":"pack_sizes.changed","occurred_at":"2025-07-01T09:00:00Z","data":{}}`),
+						Status:         domain.WebhookDeliveryFailed,
+						Attempts:       8,
+						LastError:      "connection refused",
+						CreatedAt:      createdAt,
+					}}, nil).
+					Times(1)
+			},
+			ResponseCode: http.StatusOK,
+			Deliveries:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			tc.MockFunc(testServer)
+
+			r := httptest.NewRequest(http.MethodGet, "/api/webhooks/3/deliveries", http.NoBody)
+			rw := httptest.NewRecorder()
+
+			testServer.api.ListWebhookDeliveries(rw, r, 3, ports.ListWebhookDeliveriesParams{Status: &status})
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+			if rw.Code == http.StatusOK {
+				var resp ports.WebhookDeliveriesResponse
+				require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+				require.Len(t, resp.Deliveries, tc.Deliveries)
+				require.Equal(t, "evt_1", resp.Deliveries[0].Payload.Id)
+			}
+		})
+	}
+}
+
+func TestReplayWebhookDelivery(t *testing.T) {
+	testCases := []struct {
+		Name         string
+		Delivery     domain.WebhookDelivery
+		Err          error
+		ResponseCode int
+	}{
+		{Name: "not found", Err: domain.ErrWebhookDeliveryNotFound, ResponseCode: http.StatusNotFound},
+		{
+			Name:         "not failed",
+			Delivery:     domain.WebhookDelivery{ID: 8, Status: domain.WebhookDeliverySucceeded},
+			ResponseCode: http.StatusConflict,
+		},
+		{
+			Name:         "success",
+			Delivery:     domain.WebhookDelivery{ID: 8, Status: domain.WebhookDeliveryFailed, Attempts: 8},
+			ResponseCode: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			testServer := newTestAPIServer(t)
+			repo := testServer.deps.mockedReplayDeliveryRepo.(*command.MockReplayWebhookDeliveryRepository)
+			repo.EXPECT().GetWebhookDelivery(gomock.Any(), int64(8)).Return(tc.Delivery, tc.Err).Times(1)
+			if tc.ResponseCode == http.StatusAccepted {
+				repo.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, d domain.WebhookDelivery) error {
+						require.Equal(t, domain.WebhookDeliveryPending, d.Status)
+						require.Zero(t, d.Attempts)
+						return nil
+					}).
+					Times(1)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/api/webhook-deliveries/8/replay", http.NoBody)
+			rw := httptest.NewRecorder()
+
+			testServer.api.ReplayWebhookDelivery(rw, r, 8)
+
+			require.Equal(t, tc.ResponseCode, rw.Code)
+		})
+	}
+}
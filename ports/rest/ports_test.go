@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -8,12 +9,30 @@ import (
 	"github.com/rossi1/smart-pack/app"
 	"github.com/rossi1/smart-pack/app/command"
 	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
 )
 
 type mockedDependencies struct {
 	mockedSetPackSizesRepository command.SetPackSizesRepository
 	mockedGetPackSizesRepository query.GetPackSizesRepository
+	mockedAppendAuditRepository  command.AuditRepository
+	mockedOutboxRepository       command.OutboxRepository
+	mockedListAuditRepository    query.AuditRepository
 	mockedPackCalculator         smart_calculator.PackCalculator
+	mockedRegisterWebhookRepo    command.RegisterWebhookRepository
+	mockedDeleteWebhookRepo      command.DeleteWebhookRepository
+	mockedReplayDeliveryRepo     command.ReplayWebhookDeliveryRepository
+	mockedListWebhooksRepo       query.WebhookRepository
+	mockedVersionedPackSizesRepo query.GetVersionedPackSizesRepository
+	packSizesNotifier            *query.PackSizesNotifier
+}
+
+// passthroughTransactor runs fn directly; the mocked repositories have no
+// transaction to join.
+type passthroughTransactor struct{}
+
+func (passthroughTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
 
 func newMockedDeps(t *testing.T) *mockedDependencies {
@@ -21,18 +40,42 @@ func newMockedDeps(t *testing.T) *mockedDependencies {
 	return &mockedDependencies{
 		mockedSetPackSizesRepository: command.NewMockSetPackSizesRepository(ctrl),
 		mockedGetPackSizesRepository: query.NewMockGetPackSizesRepository(ctrl),
+		mockedAppendAuditRepository:  command.NewMockAuditRepository(ctrl),
+		mockedOutboxRepository:       command.NewMockOutboxRepository(ctrl),
+		mockedListAuditRepository:    query.NewMockAuditRepository(ctrl),
 		mockedPackCalculator:         smart_calculator.NewMockPackCalculator(ctrl),
+		mockedRegisterWebhookRepo:    command.NewMockRegisterWebhookRepository(ctrl),
+		mockedDeleteWebhookRepo:      command.NewMockDeleteWebhookRepository(ctrl),
+		mockedReplayDeliveryRepo:     command.NewMockReplayWebhookDeliveryRepository(ctrl),
+		mockedListWebhooksRepo:       query.NewMockWebhookRepository(ctrl),
+		mockedVersionedPackSizesRepo: query.NewMockGetVersionedPackSizesRepository(ctrl),
+		packSizesNotifier:            query.NewPackSizesNotifier(),
 	}
 }
 
 func newTestApplication(deps *mockedDependencies) *app.Application {
+	getPackSizes := query.NewGetPackSizesHandler(deps.mockedGetPackSizesRepository)
 	return &app.Application{
+		PackSizesChanges: deps.packSizesNotifier,
 		Commands: &app.Commands{
-			SetPackSizes: command.NewSetPackSizesHandler(deps.mockedSetPackSizesRepository),
+			SetPackSizes: command.NewSetPackSizesHandler(
+				deps.mockedSetPackSizesRepository,
+				deps.mockedAppendAuditRepository,
+				deps.mockedOutboxRepository,
+				passthroughTransactor{},
+				domain.DefaultInputLimits,
+			),
+			RegisterWebhook:       command.NewRegisterWebhookHandler(deps.mockedRegisterWebhookRepo),
+			DeleteWebhook:         command.NewDeleteWebhookHandler(deps.mockedDeleteWebhookRepo),
+			ReplayWebhookDelivery: command.NewReplayWebhookDeliveryHandler(deps.mockedReplayDeliveryRepo, passthroughTransactor{}),
 		},
 		Queries: &app.Queries{
-			GetPackSizes: query.NewGetPackSizesHandler(deps.mockedGetPackSizesRepository),
+			GetPackSizes:          getPackSizes,
+			GetVersionedPackSizes: query.NewGetVersionedPackSizesHandler(deps.mockedVersionedPackSizesRepo),
+			CalculatePacks:        query.NewCalculatePacksHandler(getPackSizes, deps.mockedPackCalculator, domain.DefaultInputLimits),
+			ListAuditEntries:      query.NewListAuditEntriesHandler(deps.mockedListAuditRepository),
+			ListWebhooks:          query.NewListWebhooksHandler(deps.mockedListWebhooksRepo),
+			ListWebhookDeliveries: query.NewListWebhookDeliveriesHandler(deps.mockedListWebhooksRepo),
 		},
-		PackCalculator: deps.mockedPackCalculator,
 	}
 }
@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rossi1/smart-pack/common/requestmeta"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/ports"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimiter takes one request from the bucket stored under key.
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error)
+}
+
+// RateLimits are the limits per operation, keyed by method and path as
+// written in the API spec, e.g. "POST /calculate". Default applies to the
+// other operations; when it is zero they are not limited.
+type RateLimits struct {
+	Default domain.RateLimit
+	Routes  map[string]domain.RateLimit
+	// AuthFailures limits the failed authentications per source IP, across
+	// all operations. When it is zero they are not limited.
+	AuthFailures domain.RateLimit
+}
+
+// ParseRateLimits reads a default limit and a comma-separated list of
+// "<METHOD> <path>=<limit>" entries. Either may be empty.
+func ParseRateLimits(defaultLimit, routes string) (RateLimits, error) {
+	limits := RateLimits{Routes: map[string]domain.RateLimit{}}
+	if strings.TrimSpace(defaultLimit) != "" {
+		l, err := domain.ParseRateLimit(defaultLimit)
+		if err != nil {
+			return RateLimits{}, err
+		}
+		limits.Default = l
+	}
+
+	for _, entry := range strings.Split(routes, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(entry, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPath {
+			return RateLimits{}, fmt.Errorf("route rate limit %q: want <METHOD> <path>=<limit>", entry)
+		}
+		l, err := domain.ParseRateLimit(limit)
+		if err != nil {
+			return RateLimits{}, err
+		}
+		limits.Routes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = l
+	}
+	return limits, nil
+}
+
+// probeRoutes are left out of the default limit: orchestrators poll them
+// from a few addresses and must not be turned away.
+var probeRoutes = map[string]bool{
+	"GET /health/live":  true,
+	"GET /health/ready": true,
+}
+
+func (l RateLimits) forRoute(route string) (domain.RateLimit, bool) {
+	if limit, ok := l.Routes[route]; ok {
+		return limit, true
+	}
+	if probeRoutes[route] {
+		return domain.RateLimit{}, false
+	}
+	return l.Default, l.Default.Limit > 0
+}
+
+// rateLimit gives every client its own bucket per operation. Authenticated
+// clients are told apart by their principal, everyone else by source IP.
+// When the limiter fails, requests are let through.
+func rateLimit(limiter RateLimiter, limits RateLimits) ports.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			route := r.Method + " " + routePattern(r)
+			limit, ok := limits.forRoute(route)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			d, err := limiter.Take(ctx, route+" "+rateLimitClient(ctx), limit)
+			if err != nil {
+				logrus.WithContext(ctx).WithError(err).Warn("Rate limiter failed, letting request through")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setRateLimitHeaders(w.Header(), d)
+			if !d.Allowed {
+				w.Header().Set("Retry-After", seconds(d.RetryAfter))
+				respondWithError(w, r, domain.ErrRateLimited, "Rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routePattern is the path of the matched operation, without the prefix the
+// API is mounted at.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || len(rctx.RoutePatterns) == 0 {
+		return r.URL.Path
+	}
+	return rctx.RoutePatterns[len(rctx.RoutePatterns)-1]
+}
+
+func rateLimitClient(ctx context.Context) string {
+	meta := requestmeta.FromContext(ctx)
+	if meta.Principal != nil {
+		return "principal:" + meta.Principal.Subject
+	}
+	return "ip:" + meta.SourceIP
+}
+
+// setRateLimitHeaders writes the RateLimit fields of the IETF draft
+// "RateLimit header fields for HTTP".
+func setRateLimitHeaders(h http.Header, d domain.RateLimitDecision) {
+	h.Set("RateLimit-Limit", strconv.Itoa(d.Limit.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	h.Set("RateLimit-Reset", seconds(d.Reset))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", d.Limit.Limit, seconds(d.Limit.Period)))
+}
+
+// seconds rounds d up, so clients never retry too early.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
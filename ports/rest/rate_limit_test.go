@@ -0,0 +1,167 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits("100/1m", "post /calculate=20/1s, GET /webhooks/{id}/deliveries=5/1s")
+	require.NoError(t, err)
+	require.Equal(t, RateLimits{
+		Default: domain.RateLimit{Limit: 100, Period: time.Minute},
+		Routes: map[string]domain.RateLimit{
+			"POST /calculate":               {Limit: 20, Period: time.Second},
+			"GET /webhooks/{id}/deliveries": {Limit: 5, Period: time.Second},
+		},
+	}, limits)
+
+	_, limited := limits.forRoute("GET /health/ready")
+	require.False(t, limited, "probes are not limited by default")
+
+	limits, err = ParseRateLimits("", "")
+	require.NoError(t, err)
+	_, limited = limits.forRoute("GET /pack-sizes")
+	require.False(t, limited)
+
+	for _, routes := range []string{"/calculate=20/1s", "POST /calculate", "POST /calculate=fast"} {
+		_, err := ParseRateLimits("", routes)
+		require.Error(t, err, routes)
+	}
+}
+
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Take(context.Context, string, domain.RateLimit) (domain.RateLimitDecision, error) {
+	return domain.RateLimitDecision{}, errors.New("database is down")
+}
+
+func TestHandlerRateLimit(t *testing.T) {
+	limits := RateLimits{Routes: map[string]domain.RateLimit{
+		"GET /pack-sizes": {Limit: 1, Period: time.Minute},
+	}}
+
+	newHandler := func(t *testing.T, limiter RateLimiter) http.Handler {
+		testServer := newTestAPIServer(t)
+		testServer.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+			EXPECT().GetPackSizes(gomock.Any()).
+			Return([]domain.SmartPack{{Size: 250, Enabled: true}}, nil).
+			AnyTimes()
+		return server.RequestMetadata(Handler(testServer.api.app, chi.NewRouter(), HandlerOptions{
+			RateLimiter: limiter,
+			RateLimits:  limits,
+		}))
+	}
+	serve := func(handler http.Handler, path, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = remoteAddr
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	t.Run("limits each client", func(t *testing.T) {
+		handler := newHandler(t, adapters.NewMemoryRateLimiter())
+
+		rw := serve(handler, "/pack-sizes", "192.0.2.1:1234")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "1", rw.Header().Get("RateLimit-Limit"))
+		require.Equal(t, "0", rw.Header().Get("RateLimit-Remaining"))
+		require.Equal(t, "60", rw.Header().Get("RateLimit-Reset"))
+		require.Equal(t, "1;w=60", rw.Header().Get("RateLimit-Policy"))
+
+		rw = serve(handler, "/pack-sizes", "192.0.2.1:5678")
+		require.Equal(t, http.StatusTooManyRequests, rw.Code)
+		require.Equal(t, "60", rw.Header().Get("Retry-After"))
+		var body httperr.ErrorMessageBody
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &body))
+		require.Equal(t, httperr.NewErrorMessage(domain.ErrorRateLimitedLabel, ""), body.Messages)
+
+		rw = serve(handler, "/pack-sizes", "192.0.2.2:1234")
+		require.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("tells authenticated callers apart", func(t *testing.T) {
+		keys, err := adapters.NewAPIKeyAuthenticator("dashboard:viewer:k1,reports:viewer:k2")
+		require.NoError(t, err)
+		testServer := newTestAPIServer(t)
+		testServer.deps.mockedGetPackSizesRepository.(*query.MockGetPackSizesRepository).
+			EXPECT().GetPackSizes(gomock.Any()).
+			Return([]domain.SmartPack{{Size: 250, Enabled: true}}, nil).
+			Times(2)
+		handler := server.RequestMetadata(Handler(testServer.api.app, chi.NewRouter(), HandlerOptions{
+			Authenticators: []Authenticator{keys},
+			RateLimiter:    adapters.NewMemoryRateLimiter(),
+			RateLimits:     limits,
+		}))
+
+		// Both callers share an IP, e.g. behind the same NAT.
+		for _, key := range []string{"k1", "k2"} {
+			r := httptest.NewRequest(http.MethodGet, "/pack-sizes", nil)
+			r.Header.Set(adapters.APIKeyHeader, key)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, r)
+			require.Equal(t, http.StatusOK, rw.Code)
+		}
+	})
+
+	t.Run("limits failed authentication per IP", func(t *testing.T) {
+		keys, err := adapters.NewAPIKeyAuthenticator("dashboard:viewer:k1")
+		require.NoError(t, err)
+		testServer := newTestAPIServer(t)
+		handler := server.RequestMetadata(Handler(testServer.api.app, chi.NewRouter(), HandlerOptions{
+			Authenticators: []Authenticator{keys},
+			RateLimiter:    adapters.NewMemoryRateLimiter(),
+			RateLimits:     RateLimits{AuthFailures: domain.RateLimit{Limit: 2, Period: time.Minute}},
+		}))
+		guess := func(remoteAddr string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodGet, "/pack-sizes", nil)
+			r.RemoteAddr = remoteAddr
+			r.Header.Set(adapters.APIKeyHeader, "wrong")
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, r)
+			return rw
+		}
+
+		for i := 0; i < 2; i++ {
+			require.Equal(t, http.StatusUnauthorized, guess("192.0.2.1:1234").Code)
+		}
+		rw := guess("192.0.2.1:1234")
+		require.Equal(t, http.StatusTooManyRequests, rw.Code)
+		require.Equal(t, "30", rw.Header().Get("Retry-After"))
+
+		require.Equal(t, http.StatusUnauthorized, guess("192.0.2.2:1234").Code)
+	})
+
+	t.Run("leaves other routes alone", func(t *testing.T) {
+		handler := newHandler(t, adapters.NewMemoryRateLimiter())
+
+		for i := 0; i < 3; i++ {
+			rw := serve(handler, "/health", "192.0.2.1:1234")
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Empty(t, rw.Header().Get("RateLimit-Limit"))
+		}
+	})
+
+	t.Run("lets requests through when the limiter fails", func(t *testing.T) {
+		handler := newHandler(t, failingRateLimiter{})
+
+		rw := serve(handler, "/pack-sizes", "192.0.2.1:1234")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Empty(t, rw.Header().Get("RateLimit-Limit"))
+	})
+}
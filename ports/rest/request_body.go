@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/rossi1/smart-pack/ports"
+)
+
+// limitBody answers 413 for a body above maxBytes. The body is read up front,
+// so handlers never see a partial one and a too-large body is not reported
+// as malformed.
+func limitBody(maxBytes int64) ports.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				respondWithError(w, r, domain.NewRequestBodyTooLargeError(maxBytes), "Request body too large")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondWithError(w, r, domain.NewRequestBodyTooLargeError(maxBytes), "Request body too large")
+				return
+			}
+			if err != nil {
+				httperr.BadRequest(domain.ErrorBadRequestLabel, "", err, w, r)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server"
+	"github.com/rossi1/smart-pack/pkg/server/httperr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerInputLimits(t *testing.T) {
+	const maxBodyBytes = 64
+
+	testServer := newTestAPIServer(t)
+	handler := server.RequestMetadata(Handler(testServer.api.app, chi.NewRouter(), HandlerOptions{
+		MaxBodyBytes: maxBodyBytes,
+	}))
+	post := func(path string, body []byte, contentLength int64) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.ContentLength = contentLength
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	t.Run("rejects a body above the limit", func(t *testing.T) {
+		body := []byte(`{"pack_sizes":[` + strings.Repeat("250,", 20) + `250]}`)
+
+		// Once as declared and once for a client that does not declare it.
+		for _, contentLength := range []int64{int64(len(body)), -1} {
+			rw := post("/pack-sizes", body, contentLength)
+			require.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+			var resp httperr.ErrorMessageBody
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+			require.Equal(t, []httperr.Message{
+				{Label: domain.ErrorRequestBodyTooLargeLabel, Limit: maxBodyBytes},
+			}, resp.Messages)
+		}
+	})
+
+	t.Run("names the limit an order exceeds", func(t *testing.T) {
+		body := []byte(fmt.Sprintf(`{"items_ordered":%d}`, domain.DefaultInputLimits.MaxItemsOrdered+1))
+
+		rw := post("/calculate", body, int64(len(body)))
+		require.Equal(t, http.StatusUnprocessableEntity, rw.Code)
+		var resp httperr.ErrorMessageBody
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		require.Equal(t, []httperr.Message{{
+			FormProperty: "items_ordered",
+			Label:        domain.ErrorItemsOrderedLimitLabel,
+			Limit:        int64(domain.DefaultInputLimits.MaxItemsOrdered),
+		}}, resp.Messages)
+	})
+}
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trigger_audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS prevent_audit_log_mutation();
+DROP INDEX IF EXISTS idx_audit_log_actor;
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+DROP TABLE IF EXISTS audit_log;
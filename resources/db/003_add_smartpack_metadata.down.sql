@@ -0,0 +1,5 @@
+ALTER TABLE smartpack
+    DROP COLUMN IF EXISTS enabled,
+    DROP COLUMN IF EXISTS gtin,
+    DROP COLUMN IF EXISTS sku,
+    DROP COLUMN IF EXISTS label;
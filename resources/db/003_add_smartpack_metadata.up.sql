@@ -0,0 +1,5 @@
+ALTER TABLE smartpack
+    ADD COLUMN label VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN sku VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN gtin VARCHAR(14) NOT NULL DEFAULT '',
+    ADD COLUMN enabled BOOLEAN NOT NULL DEFAULT TRUE;
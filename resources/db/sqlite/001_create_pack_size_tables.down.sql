@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_smartpack_size;
+DROP TABLE IF EXISTS smartpack;
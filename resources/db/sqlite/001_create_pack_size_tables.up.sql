@@ -0,0 +1,10 @@
+CREATE TABLE smartpack (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    size INTEGER NOT NULL CHECK (size > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+INSERT INTO smartpack (size) VALUES (250), (500), (1000), (2000), (5000);
+
+CREATE INDEX idx_smartpack_size ON smartpack (size);
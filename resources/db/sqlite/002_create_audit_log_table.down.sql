@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trigger_audit_log_no_delete;
+DROP TRIGGER IF EXISTS trigger_audit_log_no_update;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,4 @@
+ALTER TABLE smartpack DROP COLUMN enabled;
+ALTER TABLE smartpack DROP COLUMN gtin;
+ALTER TABLE smartpack DROP COLUMN sku;
+ALTER TABLE smartpack DROP COLUMN label;
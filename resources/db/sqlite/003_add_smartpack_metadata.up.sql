@@ -0,0 +1,4 @@
+ALTER TABLE smartpack ADD COLUMN label VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE smartpack ADD COLUMN sku VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE smartpack ADD COLUMN gtin VARCHAR(14) NOT NULL DEFAULT '';
+ALTER TABLE smartpack ADD COLUMN enabled BOOLEAN NOT NULL DEFAULT TRUE;
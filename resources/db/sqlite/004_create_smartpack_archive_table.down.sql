@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_smartpack_deleted_at;
+DROP TABLE IF EXISTS smartpack_archive;
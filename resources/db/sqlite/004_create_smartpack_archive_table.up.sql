@@ -0,0 +1,14 @@
+CREATE TABLE smartpack_archive (
+    id INTEGER PRIMARY KEY,
+    size INTEGER NOT NULL,
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    sku VARCHAR(64) NOT NULL DEFAULT '',
+    gtin VARCHAR(14) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP,
+    deleted_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_smartpack_archive_deleted_at ON smartpack_archive (deleted_at);
+CREATE INDEX idx_smartpack_deleted_at ON smartpack (deleted_at);
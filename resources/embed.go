@@ -0,0 +1,16 @@
+// Package resources embeds the SQL migrations so the binary does not depend
+// on its working directory.
+package resources
+
+import "embed"
+
+// Migrations holds the Postgres migrations in db and the SQLite flavour in
+// db/sqlite.
+//
+//go:embed db
+var Migrations embed.FS
+
+const (
+	PostgresMigrationsDir = "db"
+	SQLiteMigrationsDir   = "db/sqlite"
+)
@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type OutboxRepository interface {
+	command.OutboxRepository
+	command.RelayOutboxRepository
+}
+
+const contractEventType = "contract.test"
+
+// RunOutboxRepository runs the outbox contract. Events are scheduled a day
+// ahead and told apart by payload, so rows that existed before do not
+// interfere with the assertions.
+func RunOutboxRepository(t *testing.T, newRepo func(t *testing.T) OutboxRepository) {
+	t.Run("claimed events are leased until they are redelivered", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		due := contractTime()
+		payload := appendEvent(t, repo, due)
+
+		require.Nil(t, claim(t, repo, due.Add(-time.Second), due.Add(time.Hour), payload),
+			"an event is not claimed before it is due")
+
+		event := claim(t, repo, due, due.Add(time.Minute), payload)
+		require.NotNil(t, event)
+		require.Equal(t, contractEventType, event.Type)
+		require.JSONEq(t, payload, string(event.Payload))
+		require.Zero(t, event.Attempts)
+
+		require.Nil(t, claim(t, repo, due.Add(30*time.Second), due.Add(2*time.Minute), payload),
+			"a leased event is hidden from other claims")
+		require.NotNil(t, claim(t, repo, due.Add(time.Minute), due.Add(2*time.Minute), payload),
+			"an event whose lease expired unpublished is delivered again")
+
+		require.NoError(t, repo.MarkOutboxEventPublished(ctx, event.ID, due))
+	})
+
+	t.Run("failed events are retried after their delay", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		due := contractTime()
+		payload := appendEvent(t, repo, due)
+
+		event := claim(t, repo, due, due.Add(time.Minute), payload)
+		require.NotNil(t, event)
+		retryAt := due.Add(10 * time.Second)
+		require.NoError(t, repo.MarkOutboxEventFailed(ctx, event.ID, "connection refused", retryAt))
+
+		require.Nil(t, claim(t, repo, retryAt.Add(-time.Second), retryAt.Add(time.Minute), payload))
+		retried := claim(t, repo, retryAt, retryAt.Add(time.Minute), payload)
+		require.NotNil(t, retried)
+		require.Equal(t, event.ID, retried.ID)
+		require.Equal(t, 1, retried.Attempts)
+		require.Equal(t, "connection refused", retried.LastError)
+
+		require.NoError(t, repo.MarkOutboxEventPublished(ctx, event.ID, retryAt))
+	})
+
+	t.Run("published events are never claimed again", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		due := contractTime()
+		payload := appendEvent(t, repo, due)
+
+		event := claim(t, repo, due, due.Add(time.Minute), payload)
+		require.NotNil(t, event)
+		require.NoError(t, repo.MarkOutboxEventPublished(ctx, event.ID, due))
+
+		require.Nil(t, claim(t, repo, due.Add(time.Hour), due.Add(2*time.Hour), payload))
+	})
+}
+
+// contractTime is a day ahead and whole seconds, which every store keeps
+// exactly.
+func contractTime() time.Time {
+	return time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+}
+
+func appendEvent(t *testing.T, repo OutboxRepository, due time.Time) string {
+	t.Helper()
+
+	payload := fmt.Sprintf(`{"n": %d}`, time.Now().UnixNano())
+	require.NoError(t, repo.AppendOutboxEvent(context.Background(), domain.OutboxEvent{
+		Type:          contractEventType,
+		Payload:       []byte(payload),
+		CreatedAt:     due,
+		NextAttemptAt: due,
+	}))
+	return payload
+}
+
+// claim returns the event with payload among those claimed, or nil.
+func claim(t *testing.T, repo OutboxRepository, now, leaseUntil time.Time, payload string) *domain.OutboxEvent {
+	t.Helper()
+
+	events, err := repo.ClaimOutboxEvents(context.Background(), now, leaseUntil, 1000)
+	require.NoError(t, err)
+	for i := range events {
+		if events[i].Type == contractEventType && string(events[i].Payload) == payload {
+			return &events[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Package contract holds behaviour every storage adapter must satisfy. Each
+// adapter runs the suites from its own tests with a factory for a repository.
+package contract
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type PackSizesRepository interface {
+	query.GetPackSizesRepository
+	query.GetVersionedPackSizesRepository
+	command.SetPackSizesRepository
+}
+
+// RunPackSizesRepository runs the pack-size repository contract. newRepo may
+// return a repository over existing data; the tests only rely on sizes they
+// set themselves.
+func RunPackSizesRepository(t *testing.T, newRepo func(t *testing.T) PackSizesRepository) {
+	t.Run("set then get returns the new sizes largest first", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.SetPackSizes(ctx, []domain.SmartPack{
+			{Size: 23, Enabled: true},
+			{Size: 53, Enabled: true},
+			{Size: 31, Enabled: true},
+		})
+		require.NoError(t, err)
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []domain.SmartPack{
+			{Size: 53, Enabled: true},
+			{Size: 31, Enabled: true},
+			{Size: 23, Enabled: true},
+		}, sizes)
+	})
+
+	t.Run("set returns the sizes it replaced", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		first := []domain.SmartPack{{Size: 500, Enabled: true}, {Size: 250, Enabled: true}}
+		_, err := repo.SetPackSizes(ctx, first)
+		require.NoError(t, err)
+
+		previous, err := repo.SetPackSizes(ctx, []domain.SmartPack{{Size: 1000, Enabled: true}})
+		require.NoError(t, err)
+		require.Equal(t, first, previous)
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []domain.SmartPack{{Size: 1000, Enabled: true}}, sizes)
+	})
+
+	t.Run("metadata and disabled sizes round-trip", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		packs := []domain.SmartPack{
+			{Size: 1000, Label: "Crate", SKU: "CR-1000", GTIN: "4006381333931", Enabled: true},
+			{Size: 250, Label: "Box", SKU: "BOX-250", Enabled: false},
+		}
+		_, err := repo.SetPackSizes(ctx, packs)
+		require.NoError(t, err)
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, packs, sizes)
+	})
+
+	t.Run("a size removed earlier can be set again", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		for _, size := range []int{250, 500, 250} {
+			_, err := repo.SetPackSizes(ctx, []domain.SmartPack{{Size: size, Enabled: true}})
+			require.NoError(t, err)
+		}
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []domain.SmartPack{{Size: 250, Enabled: true}}, sizes)
+	})
+
+	t.Run("empty set clears the active sizes", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.SetPackSizes(ctx, []domain.SmartPack{{Size: 250, Enabled: true}})
+		require.NoError(t, err)
+
+		previous, err := repo.SetPackSizes(ctx, nil)
+		require.NoError(t, err)
+		require.Equal(t, []domain.SmartPack{{Size: 250, Enabled: true}}, previous)
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Empty(t, sizes)
+	})
+
+	t.Run("every set raises the version", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.SetPackSizes(ctx, []domain.SmartPack{{Size: 250, Enabled: true}})
+		require.NoError(t, err)
+		before, err := repo.GetVersionedPackSizes(ctx)
+		require.NoError(t, err)
+
+		_, err = repo.SetPackSizes(ctx, []domain.SmartPack{{Size: 250, Enabled: true}, {Size: 500, Enabled: false}})
+		require.NoError(t, err)
+		after, err := repo.GetVersionedPackSizes(ctx)
+		require.NoError(t, err)
+
+		require.Greater(t, after.Version, before.Version)
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Equal(t, sizes, after.Packs)
+	})
+
+	t.Run("concurrent sets never mix generations", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		const writers = 8
+		var wg sync.WaitGroup
+		errs := make(chan error, writers)
+		for i := 1; i <= writers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := repo.SetPackSizes(ctx, []domain.SmartPack{
+					{Size: i * 100, Enabled: true},
+					{Size: i, Enabled: true},
+				})
+				if err != nil {
+					errs <- fmt.Errorf("writer %d: %w", i, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		sizes, err := repo.GetPackSizes(ctx)
+		require.NoError(t, err)
+		require.Len(t, sizes, 2)
+		require.Equal(t, sizes[1].Size*100, sizes[0].Size)
+	})
+}
@@ -0,0 +1,91 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/ports/rest"
+	"github.com/stretchr/testify/require"
+)
+
+// RunRateLimiter runs the rate limiter contract. Limits refill over an hour
+// and keys are unique per run, so the assertions do not depend on timing or
+// on buckets that existed before.
+func RunRateLimiter(t *testing.T, newLimiter func(t *testing.T) rest.RateLimiter) {
+	limit := domain.RateLimit{Limit: 2, Period: time.Hour}
+
+	t.Run("refuses requests over the limit", func(t *testing.T) {
+		limiter := newLimiter(t)
+		ctx := context.Background()
+		key := contractKey()
+
+		d, err := limiter.Take(ctx, key, limit)
+		require.NoError(t, err)
+		require.True(t, d.Allowed)
+		require.Equal(t, 1, d.Remaining)
+		require.Equal(t, limit, d.Limit)
+
+		d, err = limiter.Take(ctx, key, limit)
+		require.NoError(t, err)
+		require.True(t, d.Allowed)
+		require.Equal(t, 0, d.Remaining)
+
+		d, err = limiter.Take(ctx, key, limit)
+		require.NoError(t, err)
+		require.False(t, d.Allowed)
+		require.Positive(t, d.RetryAfter)
+		require.LessOrEqual(t, d.RetryAfter, 30*time.Minute)
+	})
+
+	t.Run("keys have their own buckets", func(t *testing.T) {
+		limiter := newLimiter(t)
+		ctx := context.Background()
+		first, second := contractKey(), contractKey()
+
+		for i := 0; i < limit.Limit; i++ {
+			_, err := limiter.Take(ctx, first, limit)
+			require.NoError(t, err)
+		}
+
+		d, err := limiter.Take(ctx, second, limit)
+		require.NoError(t, err)
+		require.True(t, d.Allowed)
+	})
+
+	t.Run("concurrent requests share the limit", func(t *testing.T) {
+		limiter := newLimiter(t)
+		key := contractKey()
+
+		decisions := make([]domain.RateLimitDecision, 10)
+		errs := make([]error, len(decisions))
+		var wg sync.WaitGroup
+		for i := range decisions {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				decisions[i], errs[i] = limiter.Take(context.Background(), key, limit)
+			}()
+		}
+		wg.Wait()
+
+		allowed := 0
+		for i, d := range decisions {
+			require.NoError(t, errs[i])
+			if d.Allowed {
+				allowed++
+			}
+		}
+		require.Equal(t, limit.Limit, allowed)
+	})
+}
+
+var contractKeys atomic.Int64
+
+func contractKey() string {
+	return fmt.Sprintf("contract %d-%d", time.Now().UnixNano(), contractKeys.Add(1))
+}
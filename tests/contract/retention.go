@@ -0,0 +1,86 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type RetentionRepository interface {
+	PackSizesRepository
+	command.PurgePackSizesRepository
+}
+
+// RunRetentionRepository runs the retired-generation contract. Like
+// RunPackSizesRepository it tolerates generations that existed before.
+func RunRetentionRepository(t *testing.T, newRepo func(t *testing.T) RetentionRepository) {
+	t.Run("each set retires one generation", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		before, err := repo.ListRetiredGenerations(ctx)
+		require.NoError(t, err)
+
+		for _, sizes := range [][]int{{1, 2}, {3}, {4, 5, 6}} {
+			setSizes(t, repo, sizes...)
+			// Generations are told apart by their retirement time.
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		generations, err := repo.ListRetiredGenerations(ctx)
+		require.NoError(t, err)
+		require.Len(t, generations, len(before)+3)
+		require.Equal(t, 1, generations[0].Packs)
+		require.Equal(t, 2, generations[1].Packs)
+		require.True(t, generations[0].RetiredAt.After(generations[1].RetiredAt))
+	})
+
+	for _, archive := range []bool{false, true} {
+		name := "purge deletes the given generations only"
+		if archive {
+			name = "purge archives the given generations only"
+		}
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+			ctx := context.Background()
+
+			setSizes(t, repo, 10, 20)
+			time.Sleep(2 * time.Millisecond)
+			setSizes(t, repo, 30)
+			time.Sleep(2 * time.Millisecond)
+			setSizes(t, repo, 40)
+
+			generations, err := repo.ListRetiredGenerations(ctx)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, len(generations), 2)
+
+			purged, err := repo.PurgeGenerations(ctx, []time.Time{generations[1].RetiredAt}, archive)
+			require.NoError(t, err)
+			require.Equal(t, 2, purged)
+
+			after, err := repo.ListRetiredGenerations(ctx)
+			require.NoError(t, err)
+			require.Len(t, after, len(generations)-1)
+			require.Equal(t, generations[0], after[0])
+
+			sizes, err := repo.GetPackSizes(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []domain.SmartPack{{Size: 40, Enabled: true}}, sizes)
+		})
+	}
+}
+
+func setSizes(t *testing.T, repo PackSizesRepository, sizes ...int) {
+	t.Helper()
+
+	packs := make([]domain.SmartPack, 0, len(sizes))
+	for _, size := range sizes {
+		packs = append(packs, domain.SmartPack{Size: size, Enabled: true})
+	}
+	_, err := repo.SetPackSizes(context.Background(), packs)
+	require.NoError(t, err)
+}
@@ -0,0 +1,212 @@
+package contract
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rossi1/smart-pack/app/command"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type WebhookRepository interface {
+	command.RegisterWebhookRepository
+	command.DeleteWebhookRepository
+	command.EnqueueWebhookEventRepository
+	command.DeliverWebhooksRepository
+	command.ReplayWebhookDeliveryRepository
+	query.WebhookRepository
+}
+
+// RunWebhookRepository runs the webhook contract. Every subtest works on a
+// subscription of its own, so other subscriptions in the store are ignored.
+func RunWebhookRepository(t *testing.T, newRepo func(t *testing.T) WebhookRepository) {
+	t.Run("subscriptions are created, listed and deleted", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		sub := createSubscription(t, repo)
+		require.NotZero(t, sub.ID)
+		require.False(t, sub.CreatedAt.IsZero())
+
+		subs, err := repo.ListWebhookSubscriptions(ctx)
+		require.NoError(t, err)
+		listed := findSubscription(subs, sub.ID)
+		require.NotNil(t, listed)
+		require.Equal(t, sub.URL, listed.URL)
+		require.Equal(t, sub.Secret, listed.Secret)
+		require.Equal(t, []string{domain.EventPackSizesChanged, domain.EventCalculationCompleted}, listed.EventTypes)
+
+		require.NoError(t, repo.DeleteWebhookSubscription(ctx, sub.ID))
+		require.ErrorIs(t, repo.DeleteWebhookSubscription(ctx, sub.ID), domain.ErrWebhookNotFound)
+
+		subs, err = repo.ListWebhookSubscriptions(ctx)
+		require.NoError(t, err)
+		require.Nil(t, findSubscription(subs, sub.ID))
+	})
+
+	t.Run("an event is queued once per subscription", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		sub := createSubscription(t, repo)
+		due := contractTime()
+
+		delivery := newDelivery(sub.ID, "evt_contract_1", due)
+		require.NoError(t, repo.EnqueueWebhookDeliveries(ctx, []domain.WebhookDelivery{delivery}))
+		require.NoError(t, repo.EnqueueWebhookDeliveries(ctx, []domain.WebhookDelivery{delivery}))
+
+		deliveries := listDeliveries(t, repo, domain.WebhookDeliveryFilter{SubscriptionID: sub.ID})
+		require.Len(t, deliveries, 1)
+		require.Equal(t, "evt_contract_1", deliveries[0].EventID)
+		require.Equal(t, domain.WebhookDeliveryPending, deliveries[0].Status)
+		require.JSONEq(t, string(delivery.Payload), string(deliveries[0].Payload))
+	})
+
+	t.Run("claimed deliveries are leased and updates are kept", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		sub := createSubscription(t, repo)
+		due := contractTime()
+
+		require.NoError(t, repo.EnqueueWebhookDeliveries(ctx, []domain.WebhookDelivery{
+			newDelivery(sub.ID, "evt_contract_1", due),
+			newDelivery(sub.ID, "evt_contract_2", due.Add(time.Hour)),
+		}))
+
+		claimed := claimDeliveries(t, repo, sub.ID, due, due.Add(time.Minute))
+		require.Len(t, claimed, 1)
+		require.Equal(t, "evt_contract_1", claimed[0].EventID)
+		require.Empty(t, claimDeliveries(t, repo, sub.ID, due.Add(30*time.Second), due.Add(time.Minute)))
+
+		d := claimed[0]
+		d.Retry(due, "webhook responded with 503 Service Unavailable", 503, 1)
+		require.NoError(t, repo.UpdateWebhookDelivery(ctx, d))
+
+		got, err := repo.GetWebhookDelivery(ctx, d.ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.WebhookDeliveryFailed, got.Status)
+		require.Equal(t, 1, got.Attempts)
+		require.Equal(t, 503, got.ResponseStatus)
+		require.Equal(t, "webhook responded with 503 Service Unavailable", got.LastError)
+
+		require.NoError(t, got.Replay(due.Add(2*time.Minute)))
+		require.NoError(t, repo.UpdateWebhookDelivery(ctx, got))
+		claimed = claimDeliveries(t, repo, sub.ID, due.Add(2*time.Minute), due.Add(3*time.Minute))
+		require.Len(t, claimed, 1)
+		require.Equal(t, d.ID, claimed[0].ID)
+
+		d = claimed[0]
+		d.Succeed(due.Add(2*time.Minute), 204)
+		require.NoError(t, repo.UpdateWebhookDelivery(ctx, d))
+		got, err = repo.GetWebhookDelivery(ctx, d.ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.WebhookDeliverySucceeded, got.Status)
+		require.NotNil(t, got.DeliveredAt)
+		require.True(t, got.DeliveredAt.Equal(due.Add(2*time.Minute)))
+	})
+
+	t.Run("deliveries are filtered by status, newest first", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		sub := createSubscription(t, repo)
+		due := contractTime()
+
+		require.NoError(t, repo.EnqueueWebhookDeliveries(ctx, []domain.WebhookDelivery{
+			newDelivery(sub.ID, "evt_contract_1", due),
+			newDelivery(sub.ID, "evt_contract_2", due),
+			newDelivery(sub.ID, "evt_contract_3", due),
+		}))
+		all := listDeliveries(t, repo, domain.WebhookDeliveryFilter{SubscriptionID: sub.ID})
+		require.Len(t, all, 3)
+		require.Equal(t, "evt_contract_3", all[0].EventID)
+
+		failed := all[1]
+		failed.Retry(due, "connection refused", 0, 1)
+		require.NoError(t, repo.UpdateWebhookDelivery(ctx, failed))
+
+		got := listDeliveries(t, repo, domain.WebhookDeliveryFilter{SubscriptionID: sub.ID, Status: domain.WebhookDeliveryFailed})
+		require.Len(t, got, 1)
+		require.Equal(t, failed.ID, got[0].ID)
+
+		page := listDeliveries(t, repo, domain.WebhookDeliveryFilter{SubscriptionID: sub.ID, Limit: 1, Offset: 2})
+		require.Len(t, page, 1)
+		require.Equal(t, "evt_contract_1", page[0].EventID)
+	})
+
+	t.Run("deleting a subscription deletes its deliveries", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		sub := createSubscription(t, repo)
+
+		require.NoError(t, repo.EnqueueWebhookDeliveries(ctx, []domain.WebhookDelivery{
+			newDelivery(sub.ID, "evt_contract_1", contractTime()),
+		}))
+		d := listDeliveries(t, repo, domain.WebhookDeliveryFilter{SubscriptionID: sub.ID})[0]
+
+		require.NoError(t, repo.DeleteWebhookSubscription(ctx, sub.ID))
+		_, err := repo.GetWebhookDelivery(ctx, d.ID)
+		require.ErrorIs(t, err, domain.ErrWebhookDeliveryNotFound)
+		_, err = repo.ListWebhookDeliveries(ctx, domain.WebhookDeliveryFilter{SubscriptionID: sub.ID, Limit: 10})
+		require.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	})
+}
+
+func createSubscription(t *testing.T, repo WebhookRepository) domain.WebhookSubscription {
+	t.Helper()
+
+	sub, err := domain.NewWebhookSubscription("https://partner.example.com/hooks",
+		[]string{domain.EventPackSizesChanged, domain.EventCalculationCompleted}, "")
+	require.NoError(t, err)
+	sub, err = repo.CreateWebhookSubscription(context.Background(), sub)
+	require.NoError(t, err)
+	return sub
+}
+
+func findSubscription(subs []domain.WebhookSubscription, id int64) *domain.WebhookSubscription {
+	for i := range subs {
+		if subs[i].ID == id {
+			return &subs[i]
+		}
+	}
+	return nil
+}
+
+func newDelivery(subscriptionID int64, eventID string, due time.Time) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      domain.EventPackSizesChanged,
+		Payload:        []byte(`{"id": "` + eventID + `", "type": "pack_sizes.changed"}`),
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  due,
+		CreatedAt:      due,
+	}
+}
+
+func listDeliveries(t *testing.T, repo WebhookRepository, filter domain.WebhookDeliveryFilter) []domain.WebhookDelivery {
+	t.Helper()
+
+	if filter.Limit == 0 {
+		filter.Limit = 100
+	}
+	deliveries, err := repo.ListWebhookDeliveries(context.Background(), filter)
+	require.NoError(t, err)
+	return deliveries
+}
+
+// claimDeliveries returns the claimed deliveries of one subscription.
+func claimDeliveries(t *testing.T, repo WebhookRepository, subscriptionID int64, now, leaseUntil time.Time) []domain.WebhookDelivery {
+	t.Helper()
+
+	claimed, err := repo.ClaimWebhookDeliveries(context.Background(), now, leaseUntil, 1000)
+	require.NoError(t, err)
+	var own []domain.WebhookDelivery
+	for _, d := range claimed {
+		if d.SubscriptionID == subscriptionID {
+			own = append(own, d)
+		}
+	}
+	return own
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	appConfig "github.com/rossi1/smart-pack/config"
 	"github.com/rossi1/smart-pack/tests/testenv"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -39,7 +40,10 @@ func (s *BaseSuite) SetupSuite() {
 	s.psqlContainer, err = createPsqlContainer(s.ctx)
 	r.NoError(err)
 
-	config, err := testenv.NewConfig(testenv.WithDatabaseURL(s.psqlContainer.ConnStr))
+	config, err := testenv.NewConfig(
+		testenv.WithDatabaseURL(s.psqlContainer.ConnStr),
+		testenv.WithPubSubURI(appConfig.BrokerMemory),
+	)
 	r.NoError(err)
 
 	s.TestApp, err = testenv.New(config)
@@ -0,0 +1,52 @@
+package stories
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rossi1/smart-pack/common/requestmeta"
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/server"
+	restapi "github.com/rossi1/smart-pack/ports"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) TestAuditLogRecordsPackSizeChanges() {
+	r := require.New(s.T())
+
+	actor := "audit-story"
+	requestID := "audit-story-request"
+	withActor := func(_ context.Context, req *http.Request) error {
+		req.Header.Set(server.ActorHeader, actor)
+		req.Header.Set("X-Request-Id", requestID)
+		return nil
+	}
+
+	current, err := s.RestClient.GetPackSizesWithResponse(s.Context())
+	r.NoError(err)
+	r.Equal(http.StatusOK, current.StatusCode())
+	defer func() {
+		_, err := s.RestClient.SetPackSizesWithResponse(s.Context(),
+			restapi.SetPackSizesRequest{PackSizes: current.JSON200.PackSizes})
+		r.NoError(err)
+	}()
+
+	setResp, err := s.RestClient.SetPackSizesWithResponse(s.Context(),
+		restapi.SetPackSizesRequest{PackSizes: []int{23, 31, 53}}, withActor)
+	r.NoError(err)
+	r.Equal(http.StatusOK, setResp.StatusCode())
+
+	resp, err := s.RestClient.GetAuditLogWithResponse(s.Context(), &restapi.GetAuditLogParams{RequestId: &requestID})
+	r.NoError(err)
+	r.Equal(http.StatusOK, resp.StatusCode())
+	r.NotNil(resp.JSON200)
+	r.NotEmpty(resp.JSON200.Entries)
+
+	entry := resp.JSON200.Entries[0]
+	r.Equal(domain.AuditActionSetPackSizes, entry.Action)
+	// The header is only a claim; without authentication nobody is the actor.
+	r.Equal(requestmeta.AnonymousActor, entry.Actor)
+	r.Equal(actor, entry.ClaimedActor)
+	r.Equal(requestID, entry.RequestId)
+	r.ElementsMatch([]restapi.PackSize{{Size: 23}, {Size: 31}, {Size: 53}}, entry.After)
+}
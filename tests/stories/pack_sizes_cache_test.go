@@ -0,0 +1,55 @@
+package stories
+
+import (
+	"context"
+	"time"
+
+	"github.com/rossi1/smart-pack/adapters"
+	"github.com/rossi1/smart-pack/app/query"
+	"github.com/rossi1/smart-pack/domain"
+)
+
+func (s *Suite) TestPackSizesCacheInvalidatedByNotify() {
+	r := s.Require()
+	repo := s.Repos.SmartPackRepository
+
+	current, err := repo.GetPackSizes(s.Context())
+	r.NoError(err)
+	defer func() {
+		_, err := repo.SetPackSizes(s.Context(), current)
+		r.NoError(err)
+	}()
+
+	// A second replica: its own cache, fed by its own listener.
+	listener := adapters.NewPostgresListener(s.Dependencies.DB, adapters.PackSizesChannel)
+	cache := query.NewPackSizesCache(query.NewGetPackSizesHandler(repo))
+	notified := make(chan struct{}, 16)
+	listener.Subscribe(func() {
+		cache.Invalidate()
+		notified <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(s.Context())
+	defer cancel()
+	listener.Start(ctx)
+	defer listener.Close()
+
+	// The first call comes from the initial LISTEN.
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		s.FailNow("listener did not connect")
+	}
+
+	cached, err := cache.Handle(s.Context(), &query.GetPackSizesQuery{})
+	r.NoError(err)
+	r.Equal(current, cached)
+
+	_, err = repo.SetPackSizes(s.Context(), []domain.SmartPack{{Size: 23, Enabled: true}})
+	r.NoError(err)
+
+	r.Eventually(func() bool {
+		sizes, err := cache.Handle(s.Context(), &query.GetPackSizesQuery{})
+		return err == nil && len(sizes) == 1 && sizes[0].Size == 23
+	}, 5*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,56 @@
+package stories
+
+import (
+	"testing"
+
+	"github.com/rossi1/smart-pack/ports/rest"
+	"github.com/rossi1/smart-pack/tests/contract"
+)
+
+func (s *Suite) TestPostgresSmartPackRepositoryContract() {
+	repo := s.Repos.SmartPackRepository
+
+	current, err := repo.GetPackSizes(s.Context())
+	s.Require().NoError(err)
+	defer func() {
+		_, err := repo.SetPackSizes(s.Context(), current)
+		s.Require().NoError(err)
+	}()
+
+	contract.RunPackSizesRepository(s.T(), func(t *testing.T) contract.PackSizesRepository {
+		return repo
+	})
+}
+
+func (s *Suite) TestPostgresSmartPackRepositoryRetentionContract() {
+	repo := s.Repos.SmartPackRepository
+
+	current, err := repo.GetPackSizes(s.Context())
+	s.Require().NoError(err)
+	defer func() {
+		_, err := repo.SetPackSizes(s.Context(), current)
+		s.Require().NoError(err)
+	}()
+
+	contract.RunRetentionRepository(s.T(), func(t *testing.T) contract.RetentionRepository {
+		return repo
+	})
+}
+
+func (s *Suite) TestPostgresOutboxRepositoryContract() {
+	contract.RunOutboxRepository(s.T(), func(t *testing.T) contract.OutboxRepository {
+		return s.Repos.OutboxRepository
+	})
+}
+
+func (s *Suite) TestPostgresWebhookRepositoryContract() {
+	contract.RunWebhookRepository(s.T(), func(t *testing.T) contract.WebhookRepository {
+		return s.Repos.WebhookRepository
+	})
+}
+
+func (s *Suite) TestPostgresRateLimiterContract() {
+	contract.RunRateLimiter(s.T(), func(t *testing.T) rest.RateLimiter {
+		return s.Repos.RateLimiter
+	})
+}
@@ -1,6 +1,7 @@
 package stories
 
 import (
+	"context"
 	"net/http"
 
 	restapi "github.com/rossi1/smart-pack/ports"
@@ -28,5 +29,65 @@ func (s *Suite) TestSetPackSizeError() {
 
 	resp, err := s.RestClient.SetPackSizesWithResponse(s.Context(), req)
 	r.NoError(err)
-	r.Equal(http.StatusInternalServerError, resp.StatusCode())
+	r.Equal(http.StatusBadRequest, resp.StatusCode())
+}
+
+func (s *Suite) TestSetPackSizesProblemDetails() {
+	r := require.New(s.T())
+
+	req := restapi.SetPackSizesRequest{
+		PackSizes: []int{0, 100, 100},
+	}
+	acceptProblem := func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Accept", "application/problem+json")
+		return nil
+	}
+
+	resp, err := s.RestClient.SetPackSizesWithResponse(s.Context(), req, acceptProblem)
+	r.NoError(err)
+	r.Equal(http.StatusBadRequest, resp.StatusCode())
+	r.NotNil(resp.ApplicationproblemJSON400)
+	r.Len(resp.ApplicationproblemJSON400.Errors, 2)
+	r.NotEmpty(resp.ApplicationproblemJSON400.Instance)
+}
+
+func (s *Suite) TestSetPackSizesWithMetadata() {
+	r := require.New(s.T())
+
+	current, err := s.RestClient.GetPackSizesWithResponse(s.Context())
+	r.NoError(err)
+	r.Equal(http.StatusOK, current.StatusCode())
+	defer func() {
+		_, err := s.RestClient.SetPackSizesWithResponse(s.Context(),
+			restapi.SetPackSizesRequest{PackSizes: current.JSON200.PackSizes})
+		r.NoError(err)
+	}()
+
+	label, sku, gtin := "Small box", "BOX-250", "4006381333931"
+	disabled := false
+	req := restapi.SetPackSizesRequest{
+		Packs: []restapi.PackSizeInput{
+			{Size: 250, Label: &label, Sku: &sku, Gtin: &gtin},
+			{Size: 500, Enabled: &disabled},
+		},
+	}
+
+	resp, err := s.RestClient.SetPackSizesWithResponse(s.Context(), req)
+	r.NoError(err)
+	r.Equal(http.StatusOK, resp.StatusCode())
+
+	sizes, err := s.RestClient.GetPackSizesWithResponse(s.Context())
+	r.NoError(err)
+	r.Equal(http.StatusOK, sizes.StatusCode())
+	r.Equal([]int{250}, sizes.JSON200.PackSizes)
+	r.ElementsMatch([]restapi.PackSize{
+		{Size: 250, Label: &label, Sku: &sku, Gtin: &gtin, Enabled: true},
+		{Size: 500, Enabled: false},
+	}, sizes.JSON200.Packs)
+
+	calc, err := s.RestClient.CalculatePacksWithResponse(s.Context(), restapi.CalculateRequest{ItemsOrdered: 400})
+	r.NoError(err)
+	r.Equal(http.StatusOK, calc.StatusCode())
+	r.Equal(map[string]int{"250": 2}, calc.JSON200.Packs)
+	r.Equal(&label, calc.JSON200.PackDetails[0].Label)
 }
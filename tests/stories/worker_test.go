@@ -0,0 +1,64 @@
+package stories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rossi1/smart-pack/domain"
+	"github.com/rossi1/smart-pack/pkg/broker"
+	"github.com/rossi1/smart-pack/ports/messaging"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) TestWorkerPublishesPackPlan() {
+	r := require.New(s.T())
+	replies := s.subscribe("test.replies.plan")
+
+	r.NoError(s.Broker.Publish(s.Context(), broker.Message{
+		Subject: s.Config.Cfg.WorkerOrderSubject,
+		Reply:   "test.replies.plan",
+		Data:    []byte(`{"order_id": "ord-750", "items_ordered": 750}`),
+	}))
+
+	msg := s.receive(replies)
+	var plan messaging.PackPlan
+	r.NoError(json.Unmarshal(msg.Data, &plan))
+	r.Equal("ord-750", plan.OrderID)
+	r.Equal(750, plan.TotalItems)
+	r.Equal(2, plan.TotalPacks)
+}
+
+func (s *Suite) TestWorkerDeadLettersInvalidOrder() {
+	r := require.New(s.T())
+	deadLetters := s.subscribe(s.Config.Cfg.WorkerDeadLetterSubject)
+
+	r.NoError(s.Broker.Publish(s.Context(), broker.Message{
+		Subject: s.Config.Cfg.WorkerOrderSubject,
+		Data:    []byte(`{"order_id": "ord-0", "items_ordered": 0}`),
+	}))
+
+	msg := s.receive(deadLetters)
+	r.Equal(domain.ErrorInvalidItemsOrderedLabel, msg.Header[messaging.DeadLetterReasonHeader])
+}
+
+func (s *Suite) subscribe(subject string) <-chan broker.Message {
+	ctx, cancel := context.WithCancel(s.Context())
+	s.T().Cleanup(cancel)
+
+	messages := make(chan broker.Message, 10)
+	s.Require().NoError(s.Broker.Subscribe(ctx, subject, "", func(ctx context.Context, msg broker.Message) {
+		messages <- msg
+	}))
+	return messages
+}
+
+func (s *Suite) receive(messages <-chan broker.Message) broker.Message {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		s.FailNow("no message published")
+		return broker.Message{}
+	}
+}
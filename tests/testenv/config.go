@@ -64,6 +64,15 @@ func WithDatabaseURL(url string) Option {
 	}
 }
 
+// WithPubSubURI starts an order consumer on the broker at uri, e.g.
+// config.BrokerMemory.
+func WithPubSubURI(uri string) Option {
+	return func(c *Config) {
+		c.PubSubURI = uri
+		c.Cfg.BrokerURL = uri
+	}
+}
+
 func findAndSetWorkingDirectory(filename string) (bool, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -3,7 +3,7 @@ package testenv
 import (
 	"context"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rossi1/smart-pack/adapters"
 	"github.com/rossi1/smart-pack/cmd"
 	cfg "github.com/rossi1/smart-pack/config"
@@ -21,7 +21,7 @@ func newTestDependencies(
 	manager := adapters.NewMigrationManager(config.Cfg.DatabaseURL,
 		config.Cfg.DatabaseMigrationPath)
 
-	if err := manager.LoadMigrations(); err != nil {
+	if _, err := manager.LoadMigrations(); err != nil {
 		return nil, err
 	}
 
@@ -30,12 +30,12 @@ func newTestDependencies(
 	}, nil
 }
 
-func createPostgresClient(ctx context.Context, config *cfg.AppConfig) (*pgx.Conn, error) {
-	dbOptions, err := pgx.ParseConfig(config.DatabaseURL)
+func createPostgresClient(ctx context.Context, config *cfg.AppConfig) (*pgxpool.Pool, error) {
+	dbOptions, err := pgxpool.ParseConfig(config.DatabaseURL)
 	if err != nil {
 		return nil, err
 	}
-	client, err := pgx.ConnectConfig(ctx, dbOptions)
+	client, err := pgxpool.NewWithConfig(ctx, dbOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -10,8 +10,10 @@ import (
 	"github.com/rossi1/smart-pack/app"
 	"github.com/rossi1/smart-pack/cmd"
 	appConfig "github.com/rossi1/smart-pack/config"
+	"github.com/rossi1/smart-pack/pkg/broker"
 	"github.com/rossi1/smart-pack/pkg/server"
 	"github.com/rossi1/smart-pack/ports"
+	"github.com/rossi1/smart-pack/ports/messaging"
 	"github.com/rossi1/smart-pack/ports/rest"
 )
 
@@ -27,11 +29,27 @@ func New(
 	application := cmd.NewApplication(ctx, config.Cfg, deps)
 
 	psqlRepo := adapters.NewSmartPackRepository(deps.DB)
+	auditRepo := adapters.NewAuditRepository(deps.DB)
+	outboxRepo := adapters.NewOutboxRepository(deps.DB)
+	webhookRepo := adapters.NewWebhookRepository(deps.DB)
+	rateLimiter := adapters.NewPostgresRateLimiter(deps.DB)
 
 	repos := NewRepositories(
 		psqlRepo,
+		auditRepo,
+		outboxRepo,
+		webhookRepo,
+		rateLimiter,
 	)
 
+	var b broker.Broker
+	if config.PubSubURI != "" {
+		b, err = startTestWorker(ctx, config.Cfg, application)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	httpServer := startTestHTTP(config.Cfg, application)
 	restClient, err := ports.NewClientWithResponses(httpServer.URL)
 	if err != nil {
@@ -41,8 +59,12 @@ func New(
 	return &TestApp{
 		close: func() {
 			httpServer.Close()
+			if b != nil {
+				b.Close()
+			}
 			deps.Close(ctx)
 		},
+		Broker:       b,
 		Config:       config,
 		Dependencies: deps,
 		RestClient:   restClient,
@@ -51,13 +73,31 @@ func New(
 	}, nil
 }
 
+func startTestWorker(
+	ctx context.Context,
+	appCfg *appConfig.AppConfig,
+	application *app.Application,
+) (broker.Broker, error) {
+	b, err := cmd.NewBroker(appCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer := messaging.NewOrderConsumer(application, b, cmd.WorkerSubjects(appCfg), cmd.WorkerRetry(appCfg))
+	if err := consumer.Start(ctx, appCfg.WorkerConcurrency); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
 func startTestHTTP(
 	appCfg *appConfig.AppConfig,
 	application *app.Application,
 ) *httptest.Server {
 	return httptest.NewServer(
 		server.GetRootRouter(appCfg, "/", rest.SwaggerPath, func(router chi.Router) http.Handler {
-			return ports.HandlerFromMux(rest.NewHTTPServer(application), router)
+			return rest.Handler(application, router, rest.HandlerOptions{})
 		}),
 	)
 }
@@ -4,18 +4,31 @@ import (
 	"github.com/rossi1/smart-pack/adapters"
 	"github.com/rossi1/smart-pack/app"
 	"github.com/rossi1/smart-pack/cmd"
+	"github.com/rossi1/smart-pack/pkg/broker"
 	restapi "github.com/rossi1/smart-pack/ports"
 )
 
 type Repositories struct {
 	SmartPackRepository *adapters.SmartPackRepository
+	AuditRepository     *adapters.AuditRepository
+	OutboxRepository    *adapters.OutboxRepository
+	WebhookRepository   *adapters.WebhookRepository
+	RateLimiter         *adapters.PostgresRateLimiter
 }
 
 func NewRepositories(
 	smartPackRepository *adapters.SmartPackRepository,
+	auditRepository *adapters.AuditRepository,
+	outboxRepository *adapters.OutboxRepository,
+	webhookRepository *adapters.WebhookRepository,
+	rateLimiter *adapters.PostgresRateLimiter,
 ) *Repositories {
 	return &Repositories{
 		SmartPackRepository: smartPackRepository,
+		AuditRepository:     auditRepository,
+		OutboxRepository:    outboxRepository,
+		WebhookRepository:   webhookRepository,
+		RateLimiter:         rateLimiter,
 	}
 }
 
@@ -25,7 +38,9 @@ type TestApp struct {
 	Application  *app.Application
 	RestClient   *restapi.ClientWithResponses
 	Repos        *Repositories
-	close        func()
+	// Broker is nil unless the config has a PubSubURI.
+	Broker broker.Broker
+	close  func()
 }
 
 func (t *TestApp) Close() {